@@ -12,7 +12,10 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/MikeLuu99/poker-arena/internal/ai"
 	"github.com/MikeLuu99/poker-arena/internal/game"
+	"github.com/MikeLuu99/poker-arena/internal/rating"
+	"github.com/MikeLuu99/poker-arena/internal/results"
 	"github.com/MikeLuu99/poker-arena/internal/server"
 	"github.com/MikeLuu99/poker-arena/internal/tournament"
 	"github.com/MikeLuu99/poker-arena/pkg/models"
@@ -20,6 +23,12 @@ import (
 )
 
 func main() {
+	// Reports over saved runs
+	if len(os.Args) > 1 && os.Args[1] == "query" {
+		results.Query(os.Args[2:])
+		return
+	}
+	
 	// Parse command line arguments
 	config := parseFlags()
 	
@@ -35,6 +44,43 @@ func main() {
 		log.Printf("Warning: Error loading .env file: %v", err)
 	}
 	
+	// Load tournament definition (players and their endpoints)
+	if config.TournamentFile != "" {
+		def, err := models.LoadTournamentDefinition(config.TournamentFile)
+		if err != nil {
+			log.Fatalf("Error loading tournament definition: %v", err)
+		}
+		if err := ai.ValidatePromptTemplates(def.Players); err != nil {
+			log.Fatalf("Error in tournament definition: %v", err)
+		}
+		ai.ConfigureRateLimits(def.RateLimits)
+		config.Definition = def
+	}
+	
+	// Record or replay model responses
+	if config.RecordFile != "" && config.ReplayFile != "" {
+		log.Fatal("--record and --replay cannot be used together")
+	}
+	if config.RecordFile != "" {
+		cassette, err := ai.RecordCassette(config.RecordFile)
+		if err != nil {
+			log.Fatalf("Error starting recording: %v", err)
+		}
+		defer cassette.Close()
+		log.Printf("Recording model responses to %s", config.RecordFile)
+	}
+	if config.ReplayFile != "" {
+		cassette, err := ai.ReplayCassette(config.ReplayFile)
+		if err != nil {
+			log.Fatalf("Error loading cassette: %v", err)
+		}
+		defer cassette.Close()
+		log.Printf("Replaying model responses from %s", config.ReplayFile)
+		if config.Seed == 0 {
+			log.Printf("Warning: replaying without --seed deals different cards than the recording")
+		}
+	}
+	
 	// Set port from environment if not set by flag
 	if config.Port == "" {
 		config.Port = os.Getenv("PORT")
@@ -43,9 +89,21 @@ func main() {
 		}
 	}
 	
+	if _, err := tournament.ExportFormat(config.OutputFile, config.OutputFormat); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	
+	if config.Resume && config.CheckpointFile == "" {
+		log.Fatal("--resume needs --checkpoint to say where the tournament was saved")
+	}
+	if config.CheckpointFile != "" && config.Definition.IsMultiTable() {
+		log.Fatal("--checkpoint is not supported for multi-table tournaments")
+	}
+	
 	// Initialize and run based on mode
-	if config.Games > 1 {
-		// Multiple games always use batch/tournament mode
+	if config.Games > 1 || config.Definition.IsMultiTable() || config.CheckpointFile != "" || config.DatabaseFile != "" {
+		// Multiple games, multi-table tournaments, checkpointed runs and
+		// runs saved to a database always use batch/tournament mode
 		runBatchMode(config)
 	} else {
 		// Single game uses single game mode
@@ -58,26 +116,47 @@ func parseFlags() *models.Config {
 	
 	flag.IntVar(&config.Games, "games", config.Games, "Number of parallel games to run")
 	flag.IntVar(&config.Games, "g", config.Games, "Number of parallel games to run (shorthand)")
-	flag.StringVar(&config.OutputFile, "output", config.OutputFile, "CSV output file path") 
-	flag.StringVar(&config.OutputFile, "o", config.OutputFile, "CSV output file path (shorthand)")
+	flag.StringVar(&config.OutputFile, "output", config.OutputFile, "Results file path (.csv, .json or .ndjson)") 
+	flag.StringVar(&config.OutputFile, "o", config.OutputFile, "Results file path (shorthand)")
+	flag.StringVar(&config.OutputFormat, "format", config.OutputFormat, "Results file format: csv, json or ndjson (default: from the --output extension)")
 	flag.BoolVar(&config.NoServer, "no-server", config.NoServer, "Disable web server for batch mode")
 	flag.BoolVar(&config.WithServers, "with-servers", config.WithServers, "Enable web servers for parallel games (ports 3000, 3001, 3002, ...)")
 	flag.BoolVar(&config.Verbose, "verbose", config.Verbose, "Enable verbose logging")
 	flag.BoolVar(&config.Verbose, "v", config.Verbose, "Enable verbose logging (shorthand)")
 	flag.StringVar(&config.Port, "port", "", "Base web server port for parallel games (default: 3000 or PORT env var)")
+	flag.StringVar(&config.TournamentFile, "tournament", config.TournamentFile, "JSON tournament definition (players, models, endpoints)")
+	flag.StringVar(&config.TournamentFile, "t", config.TournamentFile, "JSON tournament definition (shorthand)")
+	flag.Int64Var(&config.Seed, "seed", config.Seed, "Deck seed for reproducible games (game N uses seed+N-1, 0 for random)")
+	flag.DurationVar(&config.ActionDelay, "action-delay", config.ActionDelay, "Pause between actions (0 for none)")
+	flag.DurationVar(&config.ActionTimeout, "action-timeout", config.ActionTimeout, "Time limit per decision (0 for none); running out checks or folds")
+	flag.DurationVar(&config.TimeBank, "time-bank", config.TimeBank, "Extra time per player per game once a decision exceeds --action-timeout")
+	flag.StringVar(&config.RatingsFile, "ratings", config.RatingsFile, "JSON file of player ratings to update with every game (created if missing)")
+	flag.StringVar(&config.RecordFile, "record", config.RecordFile, "Record model requests and responses to a cassette file")
+	flag.StringVar(&config.ReplayFile, "replay", config.ReplayFile, "Replay model responses from a cassette file (no API calls)")
+	flag.StringVar(&config.DatabaseFile, "db", config.DatabaseFile, "Results database to add this run's games, hands and actions to (created if missing)")
+	flag.StringVar(&config.CheckpointFile, "checkpoint", config.CheckpointFile, "Save tournament progress to this file as games play")
+	flag.BoolVar(&config.Resume, "resume", config.Resume, "Resume the tournament saved in --checkpoint, skipping finished games")
 	flag.BoolVar(&config.Help, "help", config.Help, "Show help information")
 	flag.BoolVar(&config.Help, "h", config.Help, "Show help information (shorthand)")
 	
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Poker Arena - AI Poker Tournament System\n\n")
-		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s query <report> [options]  # Reports over runs saved with --db\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  %s                                    # Single game with web interface\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -g 10 -o results.csv --no-server  # 10 parallel games, save to CSV\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -g 10 -o results.ndjson --no-server  # Stream each game's result as a JSON line\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -g 3 --with-servers               # 3 parallel games with web UIs (ports 3000-3002)\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --games 50 --verbose              # 50 games with progress logging\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -t local.json -g 20 --no-server   # 20 games with players from a definition file\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -g 5 --seed 42 --record run.ndjson --no-server  # Record reproducible games\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -g 5 --seed 42 --replay run.ndjson --action-delay 0 --no-server  # Replay offline\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -g 20 --db poker_results.db --no-server  # Add the run to a results database\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -g 50 --checkpoint checkpoint.json --no-server           # Save progress as games play\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -g 50 --checkpoint checkpoint.json --resume --no-server  # Carry on after a stop or crash\n", os.Args[0])
 	}
 	
 	flag.Parse()
@@ -91,6 +170,12 @@ func runBatchMode(config *models.Config) {
 	manager := tournament.NewGameManager(config)
 	defer manager.Stop()
 	
+	// Remember ratings before the run to show how they moved
+	var ratingsBefore map[string]rating.Rating
+	if manager.Ratings() != nil {
+		ratingsBefore = manager.Ratings().Snapshot()
+	}
+	
 	// Channel to listen for interrupt signal
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
@@ -109,6 +194,7 @@ func runBatchMode(config *models.Config) {
 	select {
 	case result := <-tournamentChan:
 		printTournamentSummary(result)
+		printLeaderboard(manager.Ratings(), ratingsBefore)
 	case <-stop:
 		log.Println("Interrupt received. Stopping tournament...")
 		manager.Stop()
@@ -118,6 +204,7 @@ func runBatchMode(config *models.Config) {
 		case result := <-tournamentChan:
 			log.Println("Tournament stopped. Partial results:")
 			printTournamentSummary(result)
+			printLeaderboard(manager.Ratings(), ratingsBefore)
 		case <-time.After(5 * time.Second):
 			log.Println("Timeout waiting for tournament shutdown")
 		}
@@ -125,8 +212,26 @@ func runBatchMode(config *models.Config) {
 }
 
 func runSingleGameMode(config *models.Config) {
-	// Initialize single game
-	g := game.NewGame()
+	// Initialize single game, seating the first scheduled table if the
+	// player pool is larger than one table
+	seats := config.Seats()
+	if tables := tournament.ScheduleTables(config); tables != nil {
+		seats = tables[0]
+	}
+	g := game.NewGameWithSeats(1, seats)
+	g.SetSeed(config.GameSeed(1))
+	g.SetActionDelay(config.ActionDelay)
+	g.SetTimeControl(config.ActionTimeout, config.TimeBank)
+	g.SetCashGame(config.Definition.CashGame())
+	
+	var ratings *rating.Store
+	if config.RatingsFile != "" {
+		var err error
+		ratings, err = rating.Load(config.RatingsFile)
+		if err != nil {
+			log.Printf("Warning: Failed to load ratings, not rating this game: %v", err)
+		}
+	}
 	
 	// Initialize server
 	s := server.NewServer(g)
@@ -162,6 +267,7 @@ func runSingleGameMode(config *models.Config) {
 	case result := <-gameResultChan:
 		if result != nil {
 			printGameResult(result)
+			rateGame(ratings, result)
 		}
 		log.Println("Game completed. Shutting down server...")
 	case <-stop:
@@ -190,6 +296,18 @@ func printGameResult(result *models.GameResult) {
 	log.Printf("Total Hands: %d", result.TotalHands)
 	log.Printf("Game Duration: %s", result.GameDuration)
 	log.Printf("Eliminated Players: %v", result.Eliminated)
+	if result.CashGame {
+		for _, ranking := range result.PlayerRankings {
+			stats := result.PlayerStats[ranking.Player.Name]
+			log.Printf("%-25s | Net: %+d chips | %.1f bb/100 | Rebuys: %d",
+				ranking.Player.Name, stats.NetChips, stats.BBPer100(result.BigBlind), stats.Rebuys)
+		}
+	} else {
+		log.Println("Finishing Order:")
+		for _, ranking := range result.PlayerRankings {
+			log.Printf("  %-10s %s", ranking.Position, ranking.Player.Name)
+		}
+	}
 	log.Println(strings.Repeat("=", 60))
 }
 
@@ -197,6 +315,9 @@ func printTournamentSummary(tournament *models.TournamentResult) {
 	if tournament == nil {
 		return
 	}
+	if !tournament.IsComplete() {
+		tournament.ComputeSignificance()
+	}
 	
 	log.Println("\n" + strings.Repeat("=", 70))
 	log.Println("🏆 TOURNAMENT COMPLETED! 🏆")
@@ -204,14 +325,135 @@ func printTournamentSummary(tournament *models.TournamentResult) {
 	log.Printf("Total Games: %d", tournament.CompletedGames)
 	log.Printf("Tournament Duration: %s", tournament.TournamentDuration)
 	log.Printf("Overall Winner: %s", tournament.OverallWinner)
+	if len(tournament.InterruptedGames) > 0 {
+		log.Printf("Interrupted Games: %d (prizes scored by ICM)", len(tournament.InterruptedGames))
+	}
 	log.Println()
 	log.Println("PLAYER STATISTICS:")
 	log.Println(strings.Repeat("-", 70))
 	
+	totalCost := 0.0
 	for _, stats := range tournament.PlayerStats {
-		log.Printf("%-25s | Wins: %2d | Win Rate: %5.1f%% | Avg Rank: %.2f",
-			stats.Name, stats.Wins, stats.WinRate, stats.AvgRank)
+		log.Printf("%-25s | Wins: %2d | Win Rate: %5.1f%% | Avg Rank: %.2f | Invalid: %4.1f%% (%d defaulted)",
+			stats.Name, stats.Wins, stats.WinRate, stats.AvgRank, stats.InvalidActionRate, stats.DefaultedActions)
+		log.Printf("%-25s | 95%% CI: Win Rate %.1f-%.1f%% | Avg Rank %.2f-%.2f | Avg Chips %.1f-%.1f",
+			"", stats.WinRateCI[0], stats.WinRateCI[1], stats.AvgRankCI[0], stats.AvgRankCI[1], stats.AvgChipsCI[0], stats.AvgChipsCI[1])
+		log.Printf("%-25s | Tokens: %d in / %d out | Cost: $%.4f | Latency: %.0fms | Net: %+d chips | Chips/$: %.1f",
+			"", stats.PromptTokens, stats.CompletionTokens, stats.TotalCost, stats.AvgLatencyMs, stats.NetChips, stats.ChipsPerDollar)
+		log.Printf("%-25s | Decision time: avg %.0fms, max %dms | Timeouts: %d",
+			"", stats.AvgDecisionMs, stats.MaxDecisionMs, stats.Timeouts)
+		style := stats.Style
+		log.Printf("%-25s | Style: VPIP %.0f%% | PFR %.0f%% | 3-bet %.0f%% | AF %.1f | Fold to raise %.0f%% | WTSD %.0f%% | W$SD %.0f%% | C-bet %.0f%% | Avg raise %.1fbb (%d hands)",
+			"", style.VPIP, style.PFR, style.ThreeBet, style.AF, style.FoldToRaise, style.WTSD, style.WSD, style.CBet, style.AvgRaiseBB, style.Hands)
+		if len(tournament.Payouts) > 0 {
+			log.Printf("%-25s | Prizes: %.2f total, %.2f per game | In the money: %d",
+				"", stats.Winnings, stats.AvgWinnings, stats.InTheMoney)
+		}
+		if tournament.CashGame {
+			log.Printf("%-25s | Cash: %+.1f bb | %.1f bb/100 over %d hands | Rebuys: %d",
+				"", stats.NetBB, stats.BBPer100, stats.HandsPlayed, stats.Rebuys)
+		}
+		if stats.FallbackDecisions > 0 {
+			log.Printf("%-25s | Fallback decisions: %d", "", stats.FallbackDecisions)
+		}
+		if stats.Parameters != "" {
+			log.Printf("%-25s | Parameters: %s", "", stats.Parameters)
+		}
+		totalCost += stats.TotalCost
+	}
+	log.Printf("Total estimated cost: $%.4f", totalCost)
+	
+	if len(tournament.Pairwise) > 0 {
+		log.Println()
+		log.Println("PAIRWISE (sign test on finishing order, Holm-corrected p<0.05):")
+		log.Println(strings.Repeat("-", 70))
+		significant := 0
+		for _, pair := range tournament.Pairwise {
+			verdict := "no significant difference"
+			if pair.Significant {
+				verdict = pair.Better() + " is better"
+				significant++
+			}
+			log.Printf("%s vs %s: %d-%d (%d ties), p=%.3f | %s",
+				pair.PlayerA, pair.PlayerB, pair.AAhead, pair.BAhead, pair.Ties, pair.PValue, verdict)
+		}
+		if significant == 0 {
+			log.Printf("No pair differs significantly; run more games before drawing conclusions.")
+		}
+	}
+	if h2h := tournament.HeadToHead; h2h != nil && len(h2h.Players) > 1 {
+		log.Println()
+		log.Println("HEAD-TO-HEAD (row vs column):")
+		log.Println(strings.Repeat("-", 70))
+		printMatrix("Chips won", h2h.Players, func(row, column string) string {
+			return fmt.Sprintf("%+.0f", h2h.ChipsWon[row][column])
+		})
+		printMatrix("Eliminations", h2h.Players, func(row, column string) string {
+			return fmt.Sprintf("%d", h2h.Eliminations[row][column])
+		})
+		printMatrix("Finished ahead", h2h.Players, func(row, column string) string {
+			return fmt.Sprintf("%d/%d", h2h.FinishedAhead[row][column], h2h.Games[row][column])
+		})
+	}
+	if tournament.FallbackGames > 0 {
+		log.Printf("Games with fallback decisions: %d of %d (flagged FallbackUsed in results)", tournament.FallbackGames, tournament.CompletedGames)
 	}
 	
 	log.Println(strings.Repeat("=", 70))
-}
\ No newline at end of file
+}
+
+// printMatrix logs a player-by-player table. Columns are numbered to keep
+// the table narrow; the numbers follow the row order.
+func printMatrix(title string, players []string, cell func(row, column string) string) {
+	header := fmt.Sprintf("%-25s", title)
+	for i := range players {
+		header += fmt.Sprintf(" %8s", fmt.Sprintf("#%d", i+1))
+	}
+	log.Println(header)
+	for i, row := range players {
+		line := fmt.Sprintf("%-25s", fmt.Sprintf("#%d %s", i+1, row))
+		for _, column := range players {
+			value := "-"
+			if row != column {
+				value = cell(row, column)
+			}
+			line += fmt.Sprintf(" %8s", value)
+		}
+		log.Println(line)
+	}
+}
+
+// rateGame updates and saves the ratings with a finished game, and shows
+// how they moved
+func rateGame(ratings *rating.Store, result *models.GameResult) {
+	if ratings == nil {
+		return
+	}
+	before := ratings.Snapshot()
+	ratings.AddGame(result)
+	if err := ratings.Save(); err != nil {
+		log.Printf("Error saving ratings: %v", err)
+	}
+	printLeaderboard(ratings, before)
+}
+
+func printLeaderboard(ratings *rating.Store, before map[string]rating.Rating) {
+	if ratings == nil {
+		return
+	}
+	
+	log.Println()
+	log.Println("RATINGS (all runs, ranked by mu - 3*sigma):")
+	log.Println(strings.Repeat("-", 70))
+	for _, entry := range ratings.Leaderboard() {
+		r := entry.Rating
+		low, high := r.Interval()
+		change := " | new"
+		if prev, ok := before[entry.Name]; ok {
+			change = fmt.Sprintf(" | %+.2f this run", r.Mu-prev.Mu)
+		}
+		log.Printf("%2d. %-25s | %6.2f ± %.2f (95%%: %.1f-%.1f) | Score: %6.2f | Games: %d%s",
+			entry.Rank, entry.Name, r.Mu, r.Sigma, low, high, r.Conservative(), r.Games, change)
+	}
+	log.Println(strings.Repeat("=", 70))
+}
@@ -0,0 +1,333 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+const defaultMaxAttempts = 3
+
+// errNotesOnly means a reply took notes but made no action. It doesn't count
+// as an invalid attempt; the model is asked again for its action.
+var errNotesOnly = errors.New("no valid make_poker_action call in the response")
+
+const noteToolInstructions = `Use the make_poker_action function to make your decision.
+You may also call take_note to remember something about an opponent for later hands.`
+
+// Agent is one seat's connection to its model. It carries the seat's
+// memory between hands when the seat enables it.
+type Agent struct {
+	Seat   models.SeatConfig
+	Memory *Memory // nil unless the seat has memory or note-taking enabled
+
+	breaker  *breaker
+	fallback *Agent // the fallback model, if the seat has one
+
+	botRand   *rand.Rand // the random bot's RNG, nil for the global source
+	botSource *botSource // botRand's source, counting draws for snapshots
+}
+
+// NewAgent creates the agent for a seat
+func NewAgent(seat models.SeatConfig) *Agent {
+	agent := &Agent{Seat: seat, breaker: newBreaker(seat.CircuitBreaker)}
+	if seat.Memory || seat.NoteTaking {
+		agent.Memory = NewMemory(seat.Name, seat.MemoryHands)
+	}
+	if seat.Fallback != nil && seat.Fallback.Model != "" {
+		// The fallback plays as the seat and shares its memory
+		fallbackSeat := seat.Fallback.SeatConfig
+		fallbackSeat.Name = seat.Name
+		fallbackSeat.Memory = seat.Memory
+		fallbackSeat.NoteTaking = seat.NoteTaking
+		fallbackSeat.CircuitBreaker = &models.CircuitBreakerConfig{Disabled: true}
+		agent.fallback = &Agent{Seat: fallbackSeat, Memory: agent.Memory}
+	}
+	return agent
+}
+
+// ObserveHand lets the agent learn from a completed hand
+func (a *Agent) ObserveHand(hand models.HandRecord) {
+	if a.Memory != nil && a.Seat.Memory {
+		a.Memory.ObserveHand(hand)
+	}
+}
+
+// View builds the agent's view of the game, including its memory
+func (a *Agent) View(player models.Player, gameState *models.GameState) GameView {
+	view := BuildGameView(player, gameState)
+	view.NoteTaking = a.Seat.NoteTaking
+	if a.Memory != nil {
+		view.Memory = a.Memory.View()
+	}
+	return view
+}
+
+// Decide asks the model for an action in the current game state. Illegal
+// or unreadable answers are sent back to the model with the reason, up to
+// the seat's attempt limit, after which the agent checks or folds.
+//
+// When the model fails the seat's fallback decides instead, if it has one.
+// Repeated failures open the seat's circuit, and the fallback (or a
+// check-fold bot) decides without calling the model until a probe succeeds.
+//
+// ctx bounds the time for the whole decision. Once it is done Decide
+// returns ctx's error without consulting the fallback.
+func (a *Agent) Decide(ctx context.Context, player models.Player, gameState *models.GameState) (Decision, error) {
+	if !a.breaker.Allow() {
+		return a.decideFallback(ctx, player, gameState, "circuit open"), nil
+	}
+
+	decision, err := a.decide(ctx, player, gameState)
+	decision.Cost = decision.Usage.Cost(a.Seat)
+	if err == nil {
+		if a.breaker.Success() {
+			log.Printf("%s: model recovered, closing circuit", a.Seat.Name)
+		}
+		return decision, nil
+	}
+
+	if a.breaker.Failure() {
+		log.Printf("%s: circuit opened after model failure: %v", a.Seat.Name, err)
+	}
+	if ctx.Err() != nil {
+		return decision, ctx.Err()
+	}
+	if a.Seat.Fallback == nil {
+		return decision, err
+	}
+
+	fallback := a.decideFallback(ctx, player, gameState, err.Error())
+	fallback.InvalidAttempts = append(decision.InvalidAttempts, fallback.InvalidAttempts...)
+	fallback.Usage.Add(decision.Usage)
+	fallback.Cost += decision.Cost
+	return fallback, nil
+}
+
+// decideFallback lets the seat's fallback decide: its fallback model, its
+// baseline bot, or a check-fold bot if it has neither or the model fails too
+func (a *Agent) decideFallback(ctx context.Context, player models.Player, gameState *models.GameState, reason string) Decision {
+	bot := "check-fold"
+	if a.Seat.Fallback != nil && a.Seat.Fallback.Bot != "" {
+		bot = a.Seat.Fallback.Bot
+	}
+
+	var usage Usage
+	var cost float64
+	if a.fallback != nil {
+		decision, err := a.fallback.decide(ctx, player, gameState)
+		decision.Cost = decision.Usage.Cost(a.fallback.Seat)
+		if err == nil {
+			decision.Fallback = a.fallback.Seat.Model
+			decision.FallbackReason = reason
+			return decision
+		}
+		log.Printf("%s: fallback model %s failed too: %v", a.Seat.Name, a.fallback.Seat.Model, err)
+		usage, cost = decision.Usage, decision.Cost
+		reason += "; fallback model: " + err.Error()
+	}
+
+	decision := botDecision(bot, a.View(player, gameState), a.botRand)
+	decision.Fallback = "bot:" + bot
+	decision.FallbackReason = reason
+	decision.Usage = usage
+	decision.Cost = cost
+	return decision
+}
+
+// decide runs the request/validate loop against the seat's own model
+func (a *Agent) decide(ctx context.Context, player models.Player, gameState *models.GameState) (Decision, error) {
+	seat := a.Seat
+	apiKey, err := resolveAPIKey(seat)
+	if err != nil {
+		return Decision{Action: "fold"}, err
+	}
+
+	view := a.View(player, gameState)
+	prompt, err := BuildPrompt(seat, view)
+	if err != nil {
+		return Decision{Action: "fold"}, err
+	}
+
+	maxAttempts := seat.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	useTools := !seat.NoTools && !toolsUnsupported(seat)
+	messages := []Message{firstMessage(seat, prompt, useTools)}
+	var invalid []InvalidAttempt
+	var usage Usage
+
+	// requestSeat stops offering take_note once the model has used a reply
+	// only for notes, which forces the action tool on the follow-up
+	requestSeat := seat
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		message, used, err := requestCompletion(ctx, requestSeat, apiKey, messages, view, useTools)
+		usage.Add(used)
+		if err == errToolsUnsupported {
+			log.Printf("%s: server rejected tool calling, retrying with JSON prompt", seat.Name)
+			useTools = false
+			messages = []Message{firstMessage(seat, prompt, false)}
+			message, used, err = requestCompletion(ctx, requestSeat, apiKey, messages, view, useTools)
+			usage.Add(used)
+			// Only a server that answers without tools is switched to the
+			// JSON prompt for good; one failing either way may just be down
+			if err == nil {
+				markToolsUnsupported(seat)
+			}
+		}
+		if err != nil {
+			return Decision{Action: "fold", InvalidAttempts: invalid, Usage: usage}, err
+		}
+
+		args, reply, err := a.parseResponse(player, message)
+		if err == errNotesOnly && requestSeat.NoteTaking {
+			requestSeat.NoteTaking = false
+			messages = append(messages,
+				Message{Role: "assistant", Content: reply},
+				Message{Role: "user", Content: fmt.Sprintf(
+					"Your notes are saved. Now call make_poker_action with your action. Legal actions: %s.",
+					view.DescribeLegalActions())},
+			)
+			attempt--
+			continue
+		}
+		if err == nil {
+			err = ValidateAction(args, view)
+		}
+		if err == nil {
+			return Decision{
+				Action:          args.Action,
+				RaiseTo:         args.RaiseAmount,
+				Reasoning:       args.Reasoning,
+				Thinking:        message.Thinking(),
+				InvalidAttempts: invalid,
+				Usage:           usage,
+			}, nil
+		}
+
+		log.Printf("%s: invalid action (attempt %d/%d): %v", seat.Name, attempt, maxAttempts, err)
+		invalid = append(invalid, InvalidAttempt{Response: reply, Error: err.Error()})
+		messages = append(messages,
+			Message{Role: "assistant", Content: reply},
+			Message{Role: "user", Content: fmt.Sprintf(
+				"That action is invalid: %s. Legal actions: %s. Please choose again.",
+				err, view.DescribeLegalActions())},
+		)
+	}
+
+	// Out of attempts: take the free option if there is one
+	fallback := Decision{Action: "fold", InvalidAttempts: invalid, Defaulted: true, Usage: usage}
+	if view.CanCheck {
+		fallback.Action = "check"
+	}
+	return fallback, nil
+}
+
+// parseResponse extracts the action from a model's reply, taking any notes
+// along the way. It also returns the reply as text, to echo back to the
+// model if the action turns out to be invalid.
+func (a *Agent) parseResponse(player models.Player, message *ResponseMessage) (ActionArgs, string, error) {
+	if message == nil {
+		return ActionArgs{}, "", fmt.Errorf("the response contained no message")
+	}
+
+	// Check if the model used function calling. With note-taking enabled
+	// the action may come alongside any number of take_note calls.
+	var actionCall *ActionArgs
+	var calls []string
+	notesOnly := len(message.ToolCalls) > 0
+	for _, toolCall := range message.ToolCalls {
+		calls = append(calls, fmt.Sprintf("%s(%s)", toolCall.Function.Name, toolCall.Function.Arguments))
+		switch toolCall.Function.Name {
+		case "make_poker_action":
+			notesOnly = false
+			var args ActionArgs
+			if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args); err == nil && actionCall == nil {
+				log.Printf("AI decision (%s): action=%s, raise_amount=%d, reasoning=%s",
+					player.Model, args.Action, args.RaiseAmount, args.Reasoning)
+				actionCall = &args
+			}
+		case "take_note":
+			var note NoteArgs
+			if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &note); err == nil {
+				a.takeNote(note)
+			}
+		default:
+			notesOnly = false
+		}
+	}
+	reply := strings.TrimSpace(message.Content)
+	if len(calls) > 0 {
+		reply = strings.TrimSpace(reply + "\n" + strings.Join(calls, "\n"))
+	}
+	if actionCall != nil {
+		return normalizeAction(*actionCall), reply, nil
+	}
+
+	responseText := strings.TrimSpace(message.Content)
+	if responseText == "" {
+		if len(calls) > 0 {
+			if notesOnly {
+				return ActionArgs{}, reply, errNotesOnly
+			}
+			return ActionArgs{}, reply, fmt.Errorf("no valid make_poker_action call in the response")
+		}
+		return ActionArgs{}, reply, fmt.Errorf("the response was empty")
+	}
+
+	// Models without tool calling are asked for a JSON object
+	if args, ok := parseJSONAction(responseText); ok {
+		log.Printf("AI decision (%s, json): action=%s, raise_amount=%d, reasoning=%s",
+			player.Model, args.Action, args.RaiseAmount, args.Reasoning)
+		for _, note := range args.Notes {
+			a.takeNote(note)
+		}
+		return normalizeAction(args), reply, nil
+	}
+
+	// Fallback to text parsing
+	log.Printf("AI decision (fallback): %s", responseText)
+
+	if strings.Contains(responseText, "call") {
+		return ActionArgs{Action: "call", Reasoning: responseText}, reply, nil
+	}
+	if strings.Contains(responseText, "raise") {
+		// Simple regex alternative for Go
+		parts := strings.Fields(responseText)
+		for i, part := range parts {
+			if part == "raise" && i+1 < len(parts) {
+				if amount, err := strconv.Atoi(strings.Trim(parts[i+1], "$")); err == nil {
+					return ActionArgs{Action: "raise", RaiseAmount: amount, Reasoning: responseText}, reply, nil
+				}
+			}
+		}
+	}
+	if strings.Contains(responseText, "fold") {
+		return ActionArgs{Action: "fold", Reasoning: responseText}, reply, nil
+	}
+
+	return ActionArgs{}, reply, fmt.Errorf("could not find an action in the response")
+}
+
+// normalizeAction cleans up the action name
+func normalizeAction(args ActionArgs) ActionArgs {
+	args.Action = strings.ToLower(strings.TrimSpace(args.Action))
+	return args
+}
+
+func (a *Agent) takeNote(note NoteArgs) {
+	if a.Memory == nil || !a.Seat.NoteTaking {
+		return
+	}
+	log.Printf("%s takes a note about %s: %s", a.Seat.Name, note.Player, note.Note)
+	a.Memory.AddNote(note.Player, note.Note)
+}
@@ -0,0 +1,101 @@
+package ai_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MikeLuu99/poker-arena/internal/ai"
+	"github.com/MikeLuu99/poker-arena/internal/ai/aitest"
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+func TestNotesOnlyReplyAsksAgainForAction(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "test")
+	srv := aitest.NewServer(aitest.PolicyFunc(func(req aitest.Request) aitest.Reply {
+		if req.OffersNotes() {
+			return aitest.NoteReply(req, ai.NoteArgs{Player: "bob", Note: "raises every hand"})
+		}
+		return aitest.ActionReply(req, ai.ActionArgs{Action: "call", Reasoning: "pot odds"})
+	}))
+	defer srv.Close()
+	defer srv.UseAsOpenRouter()()
+
+	agent := ai.NewAgent(models.SeatConfig{Name: "alice", Model: "test/notes", NoteTaking: true, MaxAttempts: 1})
+	state := &models.GameState{
+		Players: []models.Player{
+			{Name: "alice", Model: "test/notes", Chips: 90, Cards: []string{"A♠", "K♠"}},
+			{Name: "bob", Model: "test/other", Chips: 80, Cards: []string{"7♦", "2♣"}},
+		},
+		PlayerBets: map[string]int{"alice": 10, "bob": 20},
+		HandNumber: 1,
+		Round:      "preflop",
+		CurrentBet: 20,
+		BigBlind:   10,
+	}
+
+	decision, err := agent.Decide(context.Background(), state.Players[0], state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decision.Action != "call" || decision.Defaulted || len(decision.InvalidAttempts) != 0 {
+		t.Errorf("decision = %+v, want a call with no invalid attempts", decision)
+	}
+
+	requests := srv.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("made %d requests, want 2", len(requests))
+	}
+	if requests[1].OffersNotes() || !requests[1].UsesTools() {
+		t.Errorf("follow-up should offer only the action tool")
+	}
+	if notes := agent.Memory.State().Notes["bob"]; len(notes) != 1 {
+		t.Errorf("notes about bob = %v, want the one note taken", notes)
+	}
+}
+
+func TestToolsDowngradeWaitsForAnAnswerWithoutTools(t *testing.T) {
+	withoutTools := 0
+	srv := aitest.NewServer(aitest.PolicyFunc(func(req aitest.Request) aitest.Reply {
+		if req.UsesTools() {
+			return aitest.Reply{Status: 404, Body: "404 page not found"}
+		}
+		// The server is down the first time it's asked without tools
+		if withoutTools++; withoutTools == 1 {
+			return aitest.Reply{Status: 500, Body: "model runner has unexpectedly stopped"}
+		}
+		return aitest.ActionReply(req, ai.ActionArgs{Action: "call", Reasoning: "pot odds"})
+	}))
+	defer srv.Close()
+
+	seat := models.SeatConfig{Name: "alice", Model: "local/downgrade", BaseURL: srv.URL, MaxAttempts: 1}
+	agent := ai.NewAgent(seat)
+	state := &models.GameState{
+		Players: []models.Player{
+			{Name: "alice", Model: seat.Model, Chips: 90, Cards: []string{"A♠", "K♠"}},
+			{Name: "bob", Model: "test/other", Chips: 80, Cards: []string{"7♦", "2♣"}},
+		},
+		PlayerBets: map[string]int{"alice": 10, "bob": 20},
+		HandNumber: 1,
+		Round:      "preflop",
+		CurrentBet: 20,
+		BigBlind:   10,
+	}
+
+	wantTools := []bool{true, false, true, false, false}
+	for decision := 1; decision <= 3; decision++ {
+		_, err := agent.Decide(context.Background(), state.Players[0], state)
+		if (err != nil) != (decision == 1) {
+			t.Errorf("decision %d: err = %v", decision, err)
+		}
+	}
+
+	requests := srv.Requests()
+	if len(requests) != len(wantTools) {
+		t.Fatalf("made %d requests, want %d", len(requests), len(wantTools))
+	}
+	for i, want := range wantTools {
+		if requests[i].UsesTools() != want {
+			t.Errorf("request %d uses tools: %t, want %t", i+1, requests[i].UsesTools(), want)
+		}
+	}
+}
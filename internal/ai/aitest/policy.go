@@ -0,0 +1,184 @@
+package aitest
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/internal/ai"
+)
+
+// Policy decides how the server answers a request
+type Policy interface {
+	Respond(req Request) Reply
+}
+
+// PolicyFunc adapts a function to a Policy
+type PolicyFunc func(req Request) Reply
+
+// Respond calls f
+func (f PolicyFunc) Respond(req Request) Reply {
+	return f(req)
+}
+
+// Fixed always takes the same action. raiseTo is only used for raises.
+func Fixed(action string, raiseTo int) Policy {
+	return PolicyFunc(func(req Request) Reply {
+		return ActionReply(req, ai.ActionArgs{
+			Action:      action,
+			RaiseAmount: raiseTo,
+			Reasoning:   "fixed policy",
+		})
+	})
+}
+
+// RandomLegal picks uniformly among the actions the request's tool allows,
+// raising to a random amount within the allowed range. Without tools it
+// picks among check, call and fold, leaving validation to sort them out.
+func RandomLegal(seed int64) Policy {
+	var mu sync.Mutex
+	rng := rand.New(rand.NewSource(seed))
+
+	return PolicyFunc(func(req Request) Reply {
+		actions := req.LegalActions()
+		if len(actions) == 0 {
+			actions = []string{"check", "call", "fold"}
+		}
+		min, max := req.RaiseRange()
+
+		mu.Lock()
+		args := ai.ActionArgs{
+			Action:    actions[rng.Intn(len(actions))],
+			Reasoning: "random legal action",
+		}
+		if args.Action == "raise" {
+			args.RaiseAmount = min
+			if max > min {
+				args.RaiseAmount += rng.Intn(max - min + 1)
+			}
+		}
+		mu.Unlock()
+
+		return ActionReply(req, args)
+	})
+}
+
+// Text answers with plain message content and no tool call, for exercising
+// the JSON and keyword fallbacks
+func Text(content string) Policy {
+	return PolicyFunc(func(req Request) Reply {
+		return TextReply(req, content)
+	})
+}
+
+// Malformed answers with a tool call whose arguments are not valid JSON
+func Malformed() Policy {
+	return PolicyFunc(func(req Request) Reply {
+		if !req.UsesTools() {
+			return TextReply(req, `{"action": "raise", "raise_amount": `)
+		}
+		return toolCallReply(req, "make_poker_action", `{"action": "raise", "raise_amount": `)
+	})
+}
+
+// InvalidJSON answers 200 with a body that is not JSON at all
+func InvalidJSON() Policy {
+	return PolicyFunc(func(req Request) Reply {
+		return Reply{Status: http.StatusOK, Body: "<html>upstream error</html>"}
+	})
+}
+
+// Status answers every request with an HTTP error
+func Status(code int) Policy {
+	return PolicyFunc(func(req Request) Reply {
+		body := `{"error":{"code":` + strconv.Itoa(code) + `,"message":"` + http.StatusText(code) + `"}}`
+		return Reply{Status: code, Body: body}
+	})
+}
+
+// RateLimited answers the first n requests with 429 and retryAfter (seconds
+// or an HTTP date, empty for none), then hands over to next
+func RateLimited(n int, retryAfter string, next Policy) Policy {
+	var mu sync.Mutex
+	count := 0
+
+	return PolicyFunc(func(req Request) Reply {
+		mu.Lock()
+		count++
+		limited := count <= n
+		mu.Unlock()
+
+		if limited {
+			reply := Status(http.StatusTooManyRequests).Respond(req)
+			reply.RetryAfter = retryAfter
+			return reply
+		}
+		return next.Respond(req)
+	})
+}
+
+// Timeout never answers, so the client's request times out
+func Timeout() Policy {
+	return PolicyFunc(func(req Request) Reply {
+		return Reply{Hang: true}
+	})
+}
+
+// Slow answers like next after waiting d
+func Slow(d time.Duration, next Policy) Policy {
+	return PolicyFunc(func(req Request) Reply {
+		reply := next.Respond(req)
+		reply.Delay += d
+		return reply
+	})
+}
+
+// Sequence answers the i-th request with the i-th policy, repeating the
+// last one once the list runs out. It panics without any policies.
+func Sequence(policies ...Policy) Policy {
+	if len(policies) == 0 {
+		panic("aitest: Sequence needs at least one policy")
+	}
+	var mu sync.Mutex
+	next := 0
+
+	return PolicyFunc(func(req Request) Reply {
+		mu.Lock()
+		policy := policies[next]
+		if next < len(policies)-1 {
+			next++
+		}
+		mu.Unlock()
+		return policy.Respond(req)
+	})
+}
+
+// Thinking adds provider reasoning tokens (message.reasoning) to next's
+// successful responses
+func Thinking(text string, next Policy) Policy {
+	return PolicyFunc(func(req Request) Reply {
+		reply := next.Respond(req)
+		if reply.Status != http.StatusOK {
+			return reply
+		}
+
+		var response map[string]interface{}
+		if err := json.Unmarshal([]byte(reply.Body), &response); err != nil {
+			return reply
+		}
+		choices, _ := response["choices"].([]interface{})
+		for _, choice := range choices {
+			if c, ok := choice.(map[string]interface{}); ok {
+				if message, ok := c["message"].(map[string]interface{}); ok {
+					message["reasoning"] = text
+				}
+			}
+		}
+		body, _ := json.Marshal(response)
+		reply.Body = string(body)
+		return reply
+	})
+}
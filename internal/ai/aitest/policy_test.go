@@ -0,0 +1,23 @@
+package aitest
+
+import "testing"
+
+func TestSequenceRejectsEmptyList(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Sequence() with no policies should panic")
+		}
+	}()
+	Sequence()
+}
+
+func TestSequenceRepeatsLastPolicy(t *testing.T) {
+	policy := Sequence(Status(500), Status(429))
+	var got []int
+	for i := 0; i < 3; i++ {
+		got = append(got, policy.Respond(Request{}).Status)
+	}
+	if got[0] != 500 || got[1] != 429 || got[2] != 429 {
+		t.Errorf("statuses %v, want [500 429 429]", got)
+	}
+}
@@ -0,0 +1,264 @@
+// Package aitest provides an in-process stand-in for the OpenRouter chat
+// completions API. Policies script how it answers, so the real client can
+// be exercised against fixed or random legal actions, malformed output,
+// rate limits, timeouts and slow responses without network access.
+package aitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/internal/ai"
+)
+
+// Server is a fake chat completions endpoint
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	policy   Policy
+	byModel  map[string]Policy
+	requests []Request
+}
+
+// NewServer starts a fake server answering every model with policy
+func NewServer(policy Policy) *Server {
+	s := &Server{policy: policy, byModel: make(map[string]Policy)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// SetPolicy changes how requests for one model are answered
+func (s *Server) SetPolicy(model string, policy Policy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byModel[model] = policy
+}
+
+// Requests returns every request received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// UseAsOpenRouter points the AI client's OpenRouter base URL at the server
+// and returns a function that restores the previous URL
+func (s *Server) UseAsOpenRouter() func() {
+	previous := ai.OPENROUTER_BASE_URL
+	ai.OPENROUTER_BASE_URL = s.URL
+	return func() { ai.OPENROUTER_BASE_URL = previous }
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/chat/completions" {
+		http.NotFound(w, r)
+		return
+	}
+
+	var req Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":{"message":"invalid request body"}}`, http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	req.Seq = len(s.requests)
+	s.requests = append(s.requests, req)
+	policy := s.policy
+	if p, ok := s.byModel[req.Model]; ok {
+		policy = p
+	}
+	s.mu.Unlock()
+
+	reply := policy.Respond(req)
+
+	if reply.Delay > 0 {
+		select {
+		case <-time.After(reply.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if reply.Hang {
+		<-r.Context().Done()
+		return
+	}
+
+	if reply.RetryAfter != "" {
+		w.Header().Set("Retry-After", reply.RetryAfter)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	status := reply.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write([]byte(reply.Body))
+}
+
+// Request is the part of a chat completion request the policies look at
+type Request struct {
+	Seq      int          `json:"-"` // order received, starting at 0
+	Model    string       `json:"model"`
+	Messages []ai.Message `json:"messages"`
+	Tools    []Tool       `json:"tools"`
+}
+
+// Tool is a function offered in a request
+type Tool struct {
+	Function struct {
+		Name       string `json:"name"`
+		Parameters struct {
+			Properties map[string]struct {
+				Enum    []string `json:"enum"`
+				Minimum int      `json:"minimum"`
+				Maximum int      `json:"maximum"`
+			} `json:"properties"`
+		} `json:"parameters"`
+	} `json:"function"`
+}
+
+// UsesTools returns true if the request offers the action tool
+func (r Request) UsesTools() bool {
+	_, ok := r.actionTool()
+	return ok
+}
+
+// OffersNotes returns true if the request offers the take_note tool
+func (r Request) OffersNotes() bool {
+	for _, tool := range r.Tools {
+		if tool.Function.Name == "take_note" {
+			return true
+		}
+	}
+	return false
+}
+
+// LegalActions returns the actions the action tool allows, or nil when the
+// request has no tools
+func (r Request) LegalActions() []string {
+	tool, ok := r.actionTool()
+	if !ok {
+		return nil
+	}
+	return tool.Function.Parameters.Properties["action"].Enum
+}
+
+// RaiseRange returns the raise bounds the action tool allows
+func (r Request) RaiseRange() (min, max int) {
+	tool, ok := r.actionTool()
+	if !ok {
+		return 0, 0
+	}
+	amount := tool.Function.Parameters.Properties["raise_amount"]
+	return amount.Minimum, amount.Maximum
+}
+
+// Prompt returns the first user message
+func (r Request) Prompt() string {
+	for _, message := range r.Messages {
+		if message.Role == "user" {
+			return message.Content
+		}
+	}
+	return ""
+}
+
+// Retry returns true if the request is a re-prompt after an invalid answer
+func (r Request) Retry() bool {
+	return len(r.Messages) > 1
+}
+
+func (r Request) actionTool() (Tool, bool) {
+	for _, tool := range r.Tools {
+		if tool.Function.Name == "make_poker_action" {
+			return tool, true
+		}
+	}
+	return Tool{}, false
+}
+
+// Reply is how the server answers one request
+type Reply struct {
+	Status     int           // HTTP status, 200 if zero
+	RetryAfter string        // Retry-After header, if any
+	Body       string        // raw response body
+	Delay      time.Duration // wait before answering
+	Hang       bool          // never answer; the client has to time out
+}
+
+// ActionReply answers with an action: a make_poker_action tool call when
+// the request offers tools, otherwise a JSON object in the message content
+func ActionReply(req Request, args ai.ActionArgs) Reply {
+	arguments, _ := json.Marshal(args)
+	if req.UsesTools() {
+		return toolCallReply(req, "make_poker_action", string(arguments))
+	}
+	return TextReply(req, string(arguments))
+}
+
+// TextReply answers with plain message content and no tool calls
+func TextReply(req Request, content string) Reply {
+	message := map[string]interface{}{
+		"role":    "assistant",
+		"content": content,
+	}
+	return completion(req, message, len(content))
+}
+
+// NoteReply answers with only a take_note tool call and no action
+func NoteReply(req Request, args ai.NoteArgs) Reply {
+	arguments, _ := json.Marshal(args)
+	return toolCallReply(req, "take_note", string(arguments))
+}
+
+func toolCallReply(req Request, name, arguments string) Reply {
+	message := map[string]interface{}{
+		"role":    "assistant",
+		"content": "",
+		"tool_calls": []interface{}{
+			map[string]interface{}{
+				"id":   "call_" + strconv.Itoa(req.Seq),
+				"type": "function",
+				"function": map[string]string{
+					"name":      name,
+					"arguments": arguments,
+				},
+			},
+		},
+	}
+	return completion(req, message, len(arguments))
+}
+
+// completion wraps a message in a response with rough token counts
+// (four characters per token)
+func completion(req Request, message map[string]interface{}, outputLength int) Reply {
+	promptLength := 0
+	for _, m := range req.Messages {
+		promptLength += len(m.Content)
+	}
+	response := map[string]interface{}{
+		"id":     "gen-" + strconv.Itoa(req.Seq),
+		"object": "chat.completion",
+		"model":  req.Model,
+		"choices": []interface{}{
+			map[string]interface{}{
+				"index":         0,
+				"message":       message,
+				"finish_reason": "stop",
+			},
+		},
+		"usage": map[string]int{
+			"prompt_tokens":     promptLength / 4,
+			"completion_tokens": outputLength / 4,
+			"total_tokens":      (promptLength + outputLength) / 4,
+		},
+	}
+	body, _ := json.Marshal(response)
+	return Reply{Status: http.StatusOK, Body: string(body)}
+}
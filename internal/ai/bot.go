@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"hash/fnv"
+	"math/rand"
+)
+
+// Baseline bots stand in for a seat whose model is failing. They need no
+// requests and always produce a legal action.
+
+// SeedBot makes the seat's random bot reproducible: it draws from a source
+// seeded with the game seed and the seat's name, after skipping the first
+// draws values, so a restored game carries on where it left off. A seed of
+// 0 uses the global source.
+func (a *Agent) SeedBot(seed int64, draws uint64) {
+	if seed == 0 {
+		a.botRand, a.botSource = nil, nil
+		return
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(a.Seat.Name))
+	a.botSource = &botSource{source: rand.NewSource(seed ^ int64(hash.Sum64())).(rand.Source64)}
+	for a.botSource.draws < draws {
+		a.botSource.Uint64()
+	}
+	a.botRand = rand.New(a.botSource)
+}
+
+// BotDraws returns how many values the random bot has drawn from its seeded
+// source
+func (a *Agent) BotDraws() uint64 {
+	if a.botSource == nil {
+		return 0
+	}
+	return a.botSource.draws
+}
+
+// botSource counts the values drawn from a seeded source
+type botSource struct {
+	source rand.Source64
+	draws  uint64
+}
+
+func (s *botSource) Int63() int64 {
+	s.draws++
+	return s.source.Int63()
+}
+
+func (s *botSource) Uint64() uint64 {
+	s.draws++
+	return s.source.Uint64()
+}
+
+func (s *botSource) Seed(seed int64) {
+	s.source.Seed(seed)
+	s.draws = 0
+}
+
+// botDecision plays one of models.BaselineBots, drawing from rng, or the
+// global source if rng is nil
+func botDecision(bot string, view GameView, rng *rand.Rand) Decision {
+	decision := Decision{Reasoning: "baseline bot " + bot}
+
+	switch bot {
+	case "check-call":
+		switch {
+		case view.CanCheck:
+			decision.Action = "check"
+		case view.Chips >= view.ToCall:
+			decision.Action = "call"
+		default:
+			decision.Action = "fold"
+		}
+	case "random":
+		intn := rand.Intn
+		if rng != nil {
+			intn = rng.Intn
+		}
+		actions := view.LegalActions()
+		decision.Action = actions[intn(len(actions))]
+		if decision.Action == "raise" {
+			decision.RaiseTo = view.MinRaiseTo + intn(view.MaxRaiseTo-view.MinRaiseTo+1)
+		}
+	default: // check-fold
+		decision.Action = "fold"
+		if view.CanCheck {
+			decision.Action = "check"
+		}
+	}
+	return decision
+}
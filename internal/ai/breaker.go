@@ -0,0 +1,97 @@
+package ai
+
+import (
+	"sync"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+const (
+	defaultBreakerFailures = 3
+	defaultBreakerCooldown = 30 * time.Second
+)
+
+// breaker is a seat's circuit breaker. After enough consecutive failures it
+// opens and the seat stops calling its model; once the cooldown has passed
+// a single probe request is let through, which closes the circuit if it
+// succeeds and reopens it if not.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	open      bool
+	probing   bool
+	openedAt  time.Time
+}
+
+// newBreaker returns the seat's breaker, or nil if it is disabled
+func newBreaker(config *models.CircuitBreakerConfig) *breaker {
+	b := &breaker{threshold: defaultBreakerFailures, cooldown: defaultBreakerCooldown}
+	if config != nil {
+		if config.Disabled {
+			return nil
+		}
+		if config.Failures > 0 {
+			b.threshold = config.Failures
+		}
+		if config.CooldownSeconds > 0 {
+			b.cooldown = time.Duration(config.CooldownSeconds * float64(time.Second))
+		}
+	}
+	return b
+}
+
+// Allow returns true if the model may be called
+func (b *breaker) Allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return true
+	}
+	if !b.probing && time.Since(b.openedAt) >= b.cooldown {
+		b.probing = true
+		return true
+	}
+	return false
+}
+
+// Success records a working request, closing the circuit. It returns true
+// if the circuit was open.
+func (b *breaker) Success() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasOpen := b.open
+	b.failures = 0
+	b.open = false
+	b.probing = false
+	return wasOpen
+}
+
+// Failure records a failed request. It returns true if this opened (or
+// reopened) the circuit.
+func (b *breaker) Failure() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.probing || (!b.open && b.failures >= b.threshold) {
+		b.open = true
+		b.probing = false
+		b.openedAt = time.Now()
+		return true
+	}
+	return false
+}
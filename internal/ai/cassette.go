@@ -0,0 +1,234 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// A cassette records every chat completion request/response pair to a
+// file during a live run, and serves them back in replay mode without any
+// network access. Responses are keyed by model and a hash of the request
+// body, so a replayed game with the same deck seed makes the same requests
+// and gets the same answers.
+
+// CassetteEntry is one recorded request/response pair
+type CassetteEntry struct {
+	Model      string `json:"model"`
+	PromptHash string `json:"promptHash"`
+	Status     int    `json:"status"`
+	RetryAfter string `json:"retryAfter,omitempty"`
+	Body       string `json:"body"`
+}
+
+// Cassette is an http.RoundTripper that records or replays completions
+type Cassette struct {
+	replay bool
+	next   http.RoundTripper
+
+	mu      sync.Mutex
+	entries map[string][]CassetteEntry // replay: remaining responses by key
+	file    *os.File                   // record: NDJSON output
+}
+
+var (
+	activeCassette *Cassette
+	cassetteMu     sync.RWMutex
+)
+
+// RecordCassette starts recording every request to path, overwriting it
+func RecordCassette(path string) (*Cassette, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cassette: %w", err)
+	}
+	c := &Cassette{next: modelTransport, file: file}
+	setCassette(c)
+	return c, nil
+}
+
+// ReplayCassette serves every request from a recorded cassette
+func ReplayCassette(path string) (*Cassette, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cassette: %w", err)
+	}
+	defer file.Close()
+
+	c := &Cassette{replay: true, entries: make(map[string][]CassetteEntry)}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry CassetteEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("cassette %s line %d: %w", path, line, err)
+		}
+		key := cassetteKey(entry.Model, entry.PromptHash)
+		c.entries[key] = append(c.entries[key], entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	setCassette(c)
+	return c, nil
+}
+
+// Replaying returns true if requests are served from a cassette
+func (c *Cassette) Replaying() bool {
+	return c != nil && c.replay
+}
+
+// Close stops using the cassette and finishes writing it
+func (c *Cassette) Close() error {
+	cassetteMu.Lock()
+	if activeCassette == c {
+		activeCassette = nil
+	}
+	cassetteMu.Unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file != nil {
+		err := c.file.Close()
+		c.file = nil
+		return err
+	}
+	return nil
+}
+
+// RoundTrip records or replays one request
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var request struct {
+		Model string `json:"model"`
+	}
+	json.Unmarshal(body, &request)
+	hash := sha256.Sum256(body)
+	promptHash := hex.EncodeToString(hash[:])
+
+	if c.replay {
+		return c.play(req, request.Model, promptHash)
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := c.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	entry := CassetteEntry{
+		Model:      request.Model,
+		PromptHash: promptHash,
+		Status:     resp.StatusCode,
+		RetryAfter: resp.Header.Get("Retry-After"),
+		Body:       string(respBody),
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file != nil {
+		if _, err := c.file.Write(append(line, '\n')); err != nil {
+			return nil, fmt.Errorf("failed to write cassette: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+// play serves the next recorded response for the request. Identical
+// requests are answered in the order they were recorded.
+func (c *Cassette) play(req *http.Request, model, promptHash string) (*http.Response, error) {
+	key := cassetteKey(model, promptHash)
+
+	c.mu.Lock()
+	queue := c.entries[key]
+	if len(queue) == 0 {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("cassette has no recorded response for %s (prompt %.12s)", model, promptHash)
+	}
+	entry := queue[0]
+	c.entries[key] = queue[1:]
+	c.mu.Unlock()
+
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	if entry.RetryAfter != "" {
+		header.Set("Retry-After", entry.RetryAfter)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.Status, http.StatusText(entry.Status)),
+		StatusCode:    entry.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(entry.Body))),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}, nil
+}
+
+func cassetteKey(model, promptHash string) string {
+	return model + "|" + promptHash
+}
+
+func setCassette(c *Cassette) {
+	cassetteMu.Lock()
+	defer cassetteMu.Unlock()
+	activeCassette = c
+}
+
+func currentCassette() *Cassette {
+	cassetteMu.RLock()
+	defer cassetteMu.RUnlock()
+	return activeCassette
+}
+
+// responseTimeout bounds the wait for a model server to start answering,
+// and requestTimeout the whole request with its body, so a stalled
+// connection can't hang a game that has no action timeout
+const (
+	responseTimeout = 5 * time.Minute
+	requestTimeout  = 10 * time.Minute
+)
+
+// modelTransport sends model requests that aren't replayed
+var modelTransport http.RoundTripper = func() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.ResponseHeaderTimeout = responseTimeout
+	return t
+}()
+
+// transport returns the round tripper for model requests
+func transport() http.RoundTripper {
+	if c := currentCassette(); c != nil {
+		return c
+	}
+	return modelTransport
+}
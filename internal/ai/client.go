@@ -2,12 +2,12 @@ package ai
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"os"
-	"strconv"
 	"strings"
 	"time"
 
@@ -17,7 +17,6 @@ import (
 // OpenRouter API configuration
 var (
 	OPENROUTER_BASE_URL = "https://openrouter.ai/api/v1"
-	rateLimited         = false
 )
 
 type PokerActionTool struct {
@@ -37,6 +36,7 @@ type PokerActionTool struct {
 					Type        string `json:"type"`
 					Description string `json:"description"`
 					Minimum     int    `json:"minimum"`
+					Maximum     int    `json:"maximum,omitempty"`
 				} `json:"raise_amount"`
 				Reasoning struct {
 					Type        string `json:"type"`
@@ -48,48 +48,113 @@ type PokerActionTool struct {
 	} `json:"function"`
 }
 
+type NoteTool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Parameters  struct {
+			Type       string `json:"type"`
+			Properties struct {
+				Player struct {
+					Type        string `json:"type"`
+					Description string `json:"description"`
+				} `json:"player"`
+				Note struct {
+					Type        string `json:"type"`
+					Description string `json:"description"`
+				} `json:"note"`
+			} `json:"properties"`
+			Required []string `json:"required"`
+		} `json:"parameters"`
+	} `json:"function"`
+}
+
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
 type OpenRouterRequest struct {
-	Model    string `json:"model"`
-	Messages []struct {
-		Role    string `json:"role"`
-		Content string `json:"content"`
-	} `json:"messages"`
-	Tools      []PokerActionTool `json:"tools,omitempty"`
-	ToolChoice interface{}       `json:"tool_choice,omitempty"`
+	Model      string        `json:"model"`
+	Messages   []Message     `json:"messages"`
+	Tools      []interface{} `json:"tools,omitempty"`
+	ToolChoice interface{}   `json:"tool_choice,omitempty"`
+
+	Temperature     *float64                    `json:"temperature,omitempty"`
+	TopP            *float64                    `json:"top_p,omitempty"`
+	MaxTokens       int                         `json:"max_tokens,omitempty"`
+	Seed            *int64                      `json:"seed,omitempty"`
+	Reasoning       *ReasoningConfig            `json:"reasoning,omitempty"`        // OpenRouter
+	ReasoningEffort string                      `json:"reasoning_effort,omitempty"` // OpenAI-compatible servers
+	Provider        *models.ProviderPreferences `json:"provider,omitempty"`
+}
+
+type ReasoningConfig struct {
+	Effort string `json:"effort"`
+}
+
+type ResponseMessage struct {
+	Content          string `json:"content"`
+	Reasoning        string `json:"reasoning"`         // OpenRouter reasoning tokens
+	ReasoningContent string `json:"reasoning_content"` // vLLM, DeepSeek and others
+	ToolCalls        []struct {
+		Function struct {
+			Name      string `json:"name"`
+			Arguments string `json:"arguments"`
+		} `json:"function"`
+	} `json:"tool_calls"`
+}
+
+// Thinking returns the provider's reasoning tokens, if it sent any
+func (m *ResponseMessage) Thinking() string {
+	if m.Reasoning != "" {
+		return strings.TrimSpace(m.Reasoning)
+	}
+	return strings.TrimSpace(m.ReasoningContent)
 }
 
 type OpenRouterResponse struct {
 	Choices []struct {
-		Message struct {
-			Content   string `json:"content"`
-			ToolCalls []struct {
-				Function struct {
-					Name      string `json:"name"`
-					Arguments string `json:"arguments"`
-				} `json:"function"`
-			} `json:"tool_calls"`
-		} `json:"message"`
+		Message ResponseMessage `json:"message"`
 	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int     `json:"prompt_tokens"`
+		CompletionTokens int     `json:"completion_tokens"`
+		TotalTokens      int     `json:"total_tokens"`
+		Cost             float64 `json:"cost,omitempty"` // OpenRouter reports the charge in credits (USD)
+	} `json:"usage"`
 }
 
 type ActionArgs struct {
-	Action      string `json:"action"`
-	RaiseAmount int    `json:"raise_amount"`
-	Reasoning   string `json:"reasoning"`
+	Action      string     `json:"action"`
+	RaiseAmount int        `json:"raise_amount"`
+	Reasoning   string     `json:"reasoning"`
+	Notes       []NoteArgs `json:"notes,omitempty"`
+}
+
+type NoteArgs struct {
+	Player string `json:"player"`
+	Note   string `json:"note"`
 }
 
-func getPokerActionTool() PokerActionTool {
+// getPokerActionTool describes the action tool, restricted to the actions
+// and raise range that are legal in view
+func getPokerActionTool(view GameView) PokerActionTool {
 	tool := PokerActionTool{}
 	tool.Type = "function"
 	tool.Function.Name = "make_poker_action"
 	tool.Function.Description = "Make a poker action decision (fold, call, check, or raise)"
 	tool.Function.Parameters.Type = "object"
 	tool.Function.Parameters.Properties.Action.Type = "string"
-	tool.Function.Parameters.Properties.Action.Enum = []string{"fold", "call", "check", "raise"}
+	tool.Function.Parameters.Properties.Action.Enum = view.LegalActions()
 	tool.Function.Parameters.Properties.Action.Description = "The poker action to take"
 	tool.Function.Parameters.Properties.RaiseAmount.Type = "number"
 	tool.Function.Parameters.Properties.RaiseAmount.Description = "The total amount to raise to (only required if action is 'raise')"
-	tool.Function.Parameters.Properties.RaiseAmount.Minimum = 0
+	if view.MaxRaiseTo >= view.MinRaiseTo {
+		tool.Function.Parameters.Properties.RaiseAmount.Minimum = view.MinRaiseTo
+		tool.Function.Parameters.Properties.RaiseAmount.Maximum = view.MaxRaiseTo
+	}
 	tool.Function.Parameters.Properties.Reasoning.Type = "string"
 	tool.Function.Parameters.Properties.Reasoning.Description = "Brief explanation of the decision"
 	tool.Function.Parameters.Required = []string{"action"}
@@ -97,144 +162,156 @@ func getPokerActionTool() PokerActionTool {
 	return tool
 }
 
-func GetAIDecision(player models.Player, gameState *models.GameState) (string, error) {
-	apiKey := os.Getenv("OPENROUTER_API_KEY")
-	if apiKey == "" {
-		log.Printf("OPENROUTER_API_KEY is not set!")
-		return "fold", fmt.Errorf("API key not configured")
-	}
+func getNoteTool() NoteTool {
+	tool := NoteTool{}
+	tool.Type = "function"
+	tool.Function.Name = "take_note"
+	tool.Function.Description = "Save a note about an opponent (or \"general\") that will be shown to you in later hands"
+	tool.Function.Parameters.Type = "object"
+	tool.Function.Parameters.Properties.Player.Type = "string"
+	tool.Function.Parameters.Properties.Player.Description = "The opponent the note is about, or \"general\""
+	tool.Function.Parameters.Properties.Note.Type = "string"
+	tool.Function.Parameters.Properties.Note.Description = "The note, e.g. \"raises light from the button\""
+	tool.Function.Parameters.Required = []string{"player", "note"}
 
-	if rateLimited {
-		return "fold", fmt.Errorf("rate limited")
-	}
+	return tool
+}
 
-	playerCurrentBet := gameState.PlayerBets[player.Name]
-	amountToCall := gameState.CurrentBet - playerCurrentBet
-	minRaiseAmount := gameState.CurrentBet + gameState.MinRaise
-
-	prompt := fmt.Sprintf(`You are playing Texas Hold'em Poker. Analyze your situation and make a decision.
-
-Game State:
-- Your cards: %s
-- Community cards: %s
-- Current pot: $%d
-- Your chips: $%d
-- Current bet: $%d
-- Amount to call: $%d
-- Minimum raise amount: $%d
-
-Actions available:
-- fold: Give up your hand and any money already bet
-- call: Match the current bet by paying $%d
-- check: Stay in the hand without betting (only when amount to call is $0)
-- raise: Increase the bet to a higher amount
-
-Use the make_poker_action function to make your decision.`,
-		strings.Join(player.Cards, ", "),
-		strings.Join(gameState.CommunityCards, ", "),
-		gameState.Pot,
-		player.Chips,
-		gameState.CurrentBet,
-		amountToCall,
-		minRaiseAmount,
-		amountToCall)
+// firstMessage is the opening user message: the rendered prompt followed by
+// instructions for how to answer
+func firstMessage(seat models.SeatConfig, prompt string, useTools bool) Message {
+	switch {
+	case useTools && seat.NoteTaking:
+		prompt += noteToolInstructions
+	case useTools:
+		prompt += "Use the make_poker_action function to make your decision."
+	case seat.NoteTaking:
+		prompt += jsonActionWithNotesInstructions
+	default:
+		prompt += jsonActionInstructions
+	}
+	return Message{Role: "user", Content: prompt}
+}
 
+// requestCompletion sends the conversation to the seat's endpoint and
+// returns the first choice's message, or nil if the response had no choices,
+// along with the request's token usage and latency. With tools, the action
+// tool only offers the actions legal in view. ctx bounds the whole exchange,
+// but its decision clock, if any, stops while waiting for the rate limiter.
+func requestCompletion(ctx context.Context, seat models.SeatConfig, apiKey string, messages []Message, view GameView, useTools bool) (*ResponseMessage, Usage, error) {
+	var usage Usage
 	requestBody := OpenRouterRequest{
-		Model: player.Model,
-		Messages: []struct {
-			Role    string `json:"role"`
-			Content string `json:"content"`
-		}{
-			{Role: "user", Content: prompt},
-		},
-		Tools: []PokerActionTool{getPokerActionTool()},
-		ToolChoice: map[string]interface{}{
+		Model:       seat.Model,
+		Messages:    messages,
+		Temperature: seat.Temperature,
+		TopP:        seat.TopP,
+		MaxTokens:   seat.MaxTokens,
+		Seed:        seat.Seed,
+	}
+	// Reasoning effort and provider routing are spelled the OpenRouter way
+	// for OpenRouter, and the OpenAI way for everyone else
+	if seat.IsLocal() {
+		requestBody.ReasoningEffort = seat.ReasoningEffort
+	} else {
+		if seat.ReasoningEffort != "" {
+			requestBody.Reasoning = &ReasoningConfig{Effort: seat.ReasoningEffort}
+		}
+		requestBody.Provider = seat.Provider
+	}
+	if useTools {
+		requestBody.Tools = []interface{}{getPokerActionTool(view)}
+		requestBody.ToolChoice = map[string]interface{}{
 			"type": "function",
 			"function": map[string]string{
 				"name": "make_poker_action",
 			},
-		},
+		}
+		if seat.NoteTaking {
+			requestBody.Tools = append(requestBody.Tools, getNoteTool())
+			requestBody.ToolChoice = "required"
+		}
 	}
 
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
-		return "fold", err
+		return nil, usage, err
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	req, err := http.NewRequest("POST", OPENROUTER_BASE_URL+"/chat/completions", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "fold", err
-	}
+	client := &http.Client{Transport: transport(), Timeout: requestTimeout}
+	limiter := limiterFor(seat)
+	clock := decisionClock(ctx)
 
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("HTTP-Referer", "http://localhost:3000")
-	req.Header.Set("X-Title", "AI Poker Arena")
+	// Latency counts time spent on requests, not time waiting for the limiter
+	var resp *http.Response
+	var sent time.Time
+	for attempt := 0; ; attempt++ {
+		clock.pause()
+		err := limiter.Wait(ctx)
+		clock.resume()
+		if err != nil {
+			return nil, usage, err
+		}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return "fold", err
+		req, err := http.NewRequestWithContext(ctx, "POST", baseURL(seat)+"/chat/completions", bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, usage, err
+		}
+
+		if apiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+apiKey)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("HTTP-Referer", "http://localhost:3000")
+		req.Header.Set("X-Title", "AI Poker Arena")
+
+		sent = time.Now()
+		resp, err = client.Do(req)
+		usage.Requests++
+		if err != nil {
+			usage.Latency += time.Since(sent)
+			return nil, usage, err
+		}
+		if !retryable(resp.StatusCode) {
+			break
+		}
+
+		resp.Body.Close()
+		usage.Latency += time.Since(sent)
+		if attempt >= maxRateLimitRetries {
+			return nil, usage, fmt.Errorf("%s: %s after %d retries", limiter.provider, resp.Status, attempt)
+		}
+
+		// Pause the whole provider, so other seats using it wait too
+		delay := retryDelay(resp.Header.Get("Retry-After"), attempt)
+		log.Printf("%s returned %s for %s, backing off %v", limiter.provider, resp.Status, seat.Name, delay.Round(time.Millisecond))
+		if !currentCassette().Replaying() {
+			limiter.Pause(delay)
+		}
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == 429 {
-		rateLimited = true
-		go func() {
-			time.Sleep(60 * time.Second)
-			rateLimited = false
-		}()
-		return "fold", fmt.Errorf("rate limited")
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		usage.Latency += time.Since(sent)
+		if useTools && seat.IsLocal() && rejectsTools(resp.StatusCode, body) {
+			return nil, usage, errToolsUnsupported
+		}
+		return nil, usage, fmt.Errorf("%s returned %s: %s", seat.Model, resp.Status, strings.TrimSpace(string(body)))
 	}
 
 	var response OpenRouterResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "fold", err
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	usage.Latency += time.Since(sent)
+	if err != nil {
+		return nil, usage, err
 	}
 
-	if len(response.Choices) > 0 {
-		message := response.Choices[0].Message
-
-		// Check if the model used function calling
-		if len(message.ToolCalls) > 0 {
-			toolCall := message.ToolCalls[0]
-			if toolCall.Function.Name == "make_poker_action" {
-				var args ActionArgs
-				if err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args); err == nil {
-					log.Printf("AI decision (%s): action=%s, raise_amount=%d, reasoning=%s",
-						player.Model, args.Action, args.RaiseAmount, args.Reasoning)
-					if args.Action == "raise" && args.RaiseAmount > 0 {
-						return fmt.Sprintf("raise %d", args.RaiseAmount), nil
-					}
-					return args.Action, nil
-				}
-			}
-		}
+	usage.PromptTokens = response.Usage.PromptTokens
+	usage.CompletionTokens = response.Usage.CompletionTokens
+	usage.ReportedCost = response.Usage.Cost
 
-		// Fallback to text parsing
-		if message.Content != "" {
-			responseText := strings.TrimSpace(message.Content)
-			log.Printf("AI decision (fallback): %s", responseText)
-
-			if strings.Contains(responseText, "call") {
-				return "call", nil
-			}
-			if strings.Contains(responseText, "raise") {
-				// Simple regex alternative for Go
-				parts := strings.Fields(responseText)
-				for i, part := range parts {
-					if part == "raise" && i+1 < len(parts) {
-						if amount, err := strconv.Atoi(strings.Trim(parts[i+1], "$")); err == nil {
-							return fmt.Sprintf("raise %d", amount), nil
-						}
-					}
-				}
-			}
-			if strings.Contains(responseText, "fold") {
-				return "fold", nil
-			}
-		}
+	if len(response.Choices) == 0 {
+		return nil, usage, nil
 	}
-
-	return "fold", nil
-}
\ No newline at end of file
+	return &response.Choices[0].Message, usage, nil
+}
@@ -0,0 +1,118 @@
+package ai
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DecisionClock is a decision's time limit. It stops while the seat's
+// requests wait for their provider's rate limiter, including the backoff
+// after a 429, so a throttled provider doesn't make its seats run out of
+// time. Once the clock has run for the limit, its context is cancelled.
+type DecisionClock struct {
+	mu      sync.Mutex
+	limit   time.Duration
+	used    time.Duration // time run before started
+	started time.Time     // zero while stopped
+	paused  int
+	timer   *time.Timer
+	expired bool
+	cancel  context.CancelCauseFunc
+}
+
+type clockKey struct{}
+
+// WithDecisionClock returns a context that is cancelled, with cause
+// context.DeadlineExceeded, once the returned clock has run for limit. The
+// cancel function stops the clock and releases the context.
+func WithDecisionClock(parent context.Context, limit time.Duration) (context.Context, *DecisionClock, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	c := &DecisionClock{limit: limit, cancel: cancel}
+	ctx = context.WithValue(ctx, clockKey{}, c)
+	c.start()
+	return ctx, c, func() {
+		c.pause()
+		cancel(context.Canceled)
+	}
+}
+
+// decisionClock returns the clock timing ctx's decision, if any
+func decisionClock(ctx context.Context) *DecisionClock {
+	c, _ := ctx.Value(clockKey{}).(*DecisionClock)
+	return c
+}
+
+// Used returns how long the clock has run
+func (c *DecisionClock) Used() time.Duration {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	used := c.used
+	if !c.started.IsZero() {
+		used += time.Since(c.started)
+	}
+	return min(used, c.limit)
+}
+
+// Expired returns true if the decision ran out of time
+func (c *DecisionClock) Expired() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.expired
+}
+
+func (c *DecisionClock) start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.started = time.Now()
+	c.timer = time.AfterFunc(c.limit-c.used, c.expire)
+}
+
+// pause stops the clock until resume. Pauses may nest.
+func (c *DecisionClock) pause() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused++
+	if c.paused > 1 || c.started.IsZero() {
+		return
+	}
+	c.timer.Stop()
+	c.used += time.Since(c.started)
+	c.started = time.Time{}
+}
+
+func (c *DecisionClock) resume() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.paused--
+	restart := c.paused == 0 && !c.expired
+	c.mu.Unlock()
+	if restart {
+		c.start()
+	}
+}
+
+func (c *DecisionClock) expire() {
+	c.mu.Lock()
+	if c.started.IsZero() {
+		// Paused just as the timer fired
+		c.mu.Unlock()
+		return
+	}
+	c.expired = true
+	c.used = c.limit
+	c.started = time.Time{}
+	c.mu.Unlock()
+	c.cancel(context.DeadlineExceeded)
+}
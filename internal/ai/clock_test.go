@@ -0,0 +1,44 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDecisionClockStopsWhilePaused(t *testing.T) {
+	ctx, clock, cancel := WithDecisionClock(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	clock.pause()
+	time.Sleep(200 * time.Millisecond)
+	clock.resume()
+	if clock.Expired() || ctx.Err() != nil {
+		t.Fatal("clock ran while paused")
+	}
+	if used := clock.Used(); used > 50*time.Millisecond {
+		t.Errorf("clock used %v while paused", used)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("clock never ran out")
+	}
+	if !clock.Expired() || !errors.Is(context.Cause(ctx), context.DeadlineExceeded) {
+		t.Errorf("expired %v, cause %v", clock.Expired(), context.Cause(ctx))
+	}
+	if used := clock.Used(); used != 100*time.Millisecond {
+		t.Errorf("used %v after running out, want the limit", used)
+	}
+}
+
+func TestDecisionClockNil(t *testing.T) {
+	var clock *DecisionClock
+	clock.pause()
+	clock.resume()
+	if clock.Used() != 0 || clock.Expired() {
+		t.Error("a missing clock should never run")
+	}
+}
@@ -0,0 +1,45 @@
+package ai
+
+import "fmt"
+
+// Decision is an agent's chosen action
+type Decision struct {
+	Action    string // fold, call, check or raise
+	RaiseTo   int    // total bet for a raise
+	Reasoning string // the model's stated reason for the action
+	Thinking  string // reasoning tokens returned by the provider, if any
+
+	// Rejected answers before this decision, oldest first
+	InvalidAttempts []InvalidAttempt
+
+	// True if the model never gave a valid action and the agent checked
+	// or folded on its behalf
+	Defaulted bool
+
+	// Tokens and time spent on all requests for this decision, and their
+	// estimated cost in dollars
+	Usage Usage
+	Cost  float64
+
+	// The agent that decided in place of the seat's model ("" if the model
+	// did), e.g. another model's name or "bot:check-fold", and why
+	Fallback       string
+	FallbackReason string
+}
+
+// InvalidAttempt is a model answer that was rejected
+type InvalidAttempt struct {
+	Response string
+	Error    string
+}
+
+// String formats the decision the way the game engine expects it
+func (d Decision) String() string {
+	if d.Action == "raise" && d.RaiseTo > 0 {
+		return fmt.Sprintf("raise %d", d.RaiseTo)
+	}
+	if d.Action == "" {
+		return "fold"
+	}
+	return d.Action
+}
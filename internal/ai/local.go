@@ -0,0 +1,131 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// Support for locally hosted OpenAI-compatible servers (Ollama, llama.cpp,
+// vLLM, ...). These usually need no API key and often lack tool calling.
+
+const jsonActionInstructions = `Respond with only a JSON object and no other text, in this format:
+{"action": "fold|call|check|raise", "raise_amount": <total amount to raise to, only for raise>, "reasoning": "<brief explanation>"}`
+
+const jsonActionWithNotesInstructions = `Respond with only a JSON object and no other text, in this format:
+{"action": "fold|call|check|raise", "raise_amount": <total amount to raise to, only for raise>, "reasoning": "<brief explanation>", "notes": [{"player": "<opponent or general>", "note": "<what to remember>"}]}
+The "notes" list is optional; notes are shown to you in later hands.`
+
+var errToolsUnsupported = errors.New("server does not support tool calling")
+
+// Endpoints (base URL + model) that rejected a request with tools
+var noToolEndpoints sync.Map
+
+func baseURL(seat models.SeatConfig) string {
+	if seat.BaseURL != "" {
+		return strings.TrimRight(seat.BaseURL, "/")
+	}
+	return OPENROUTER_BASE_URL
+}
+
+// resolveAPIKey finds the key for a seat. OpenRouter seats require one;
+// local seats only use a key if APIKeyEnv names a variable.
+func resolveAPIKey(seat models.SeatConfig) (string, error) {
+	// Replayed responses need no credentials
+	if currentCassette().Replaying() {
+		return "", nil
+	}
+
+	if seat.APIKeyEnv != "" {
+		apiKey := os.Getenv(seat.APIKeyEnv)
+		if apiKey == "" && !seat.IsLocal() {
+			log.Printf("%s is not set!", seat.APIKeyEnv)
+			return "", fmt.Errorf("API key not configured")
+		}
+		return apiKey, nil
+	}
+
+	if seat.IsLocal() {
+		return "", nil
+	}
+
+	apiKey := os.Getenv("OPENROUTER_API_KEY")
+	if apiKey == "" {
+		log.Printf("OPENROUTER_API_KEY is not set!")
+		return "", fmt.Errorf("API key not configured")
+	}
+	return apiKey, nil
+}
+
+func endpointKey(seat models.SeatConfig) string {
+	return baseURL(seat) + "|" + seat.Model
+}
+
+func toolsUnsupported(seat models.SeatConfig) bool {
+	_, ok := noToolEndpoints.Load(endpointKey(seat))
+	return ok
+}
+
+func markToolsUnsupported(seat models.SeatConfig) {
+	noToolEndpoints.Store(endpointKey(seat), true)
+}
+
+// rejectsTools reports whether an error response is how servers typically
+// refuse a request containing tools they don't understand. A server error
+// only counts if it says so (llama.cpp answers 500 "tools param requires
+// --jinja flag"), so a transient failure isn't retried as JSON prompting.
+// The endpoint is switched to JSON prompting for good only once a request
+// without tools succeeds.
+func rejectsTools(statusCode int, body []byte) bool {
+	switch {
+	case statusCode == 400, statusCode == 404, statusCode == 422:
+		return true
+	case statusCode >= 500:
+		return strings.Contains(strings.ToLower(string(body)), "tool")
+	}
+	return false
+}
+
+// parseJSONAction extracts an action object from free-form model output,
+// tolerating code fences and surrounding chatter
+func parseJSONAction(text string) (ActionArgs, bool) {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start == -1 || end <= start {
+		return ActionArgs{}, false
+	}
+
+	// Models sometimes send the amount as a string or a float
+	var raw struct {
+		Action      string      `json:"action"`
+		RaiseAmount json.Number `json:"raise_amount"`
+		Reasoning   string      `json:"reasoning"`
+		Notes       []NoteArgs  `json:"notes"`
+	}
+	decoder := json.NewDecoder(strings.NewReader(text[start : end+1]))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return ActionArgs{}, false
+	}
+
+	args := ActionArgs{
+		Action:    strings.ToLower(strings.TrimSpace(raw.Action)),
+		Reasoning: raw.Reasoning,
+		Notes:     raw.Notes,
+	}
+	switch args.Action {
+	case "fold", "call", "check", "raise":
+	default:
+		return ActionArgs{}, false
+	}
+	if amount, err := raw.RaiseAmount.Float64(); err == nil {
+		args.RaiseAmount = int(amount)
+	}
+	return args, true
+}
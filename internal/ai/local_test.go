@@ -0,0 +1,27 @@
+package ai
+
+import "testing"
+
+func TestRejectsTools(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		want   bool
+	}{
+		{400, `{"error":"registry.ollama.ai/library/gemma:2b does not support tools"}`, true},
+		{404, "", true},
+		{422, `{"detail":"unknown field tool_choice"}`, true},
+		{500, `{"error":{"message":"tools param requires --jinja flag"}}`, true},
+		{501, "Tool calling is not implemented", true},
+		{500, "model runner has unexpectedly stopped", false},
+		{502, "Bad Gateway", false},
+		{503, "", false},
+		{401, "unauthorized", false},
+		{429, "too many requests", false},
+	}
+	for _, test := range tests {
+		if got := rejectsTools(test.status, []byte(test.body)); got != test.want {
+			t.Errorf("rejectsTools(%d, %q) = %t, want %t", test.status, test.body, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+const (
+	defaultMemoryHands = 10
+	maxNotesPerSubject = 5
+	maxNoteLength      = 300
+)
+
+// Memory is a seat's knowledge carried across hands: summaries of recent
+// hands, opponents' observed tendencies and the model's own notes
+type Memory struct {
+	mu        sync.Mutex
+	self      string
+	limit     int
+	hands     []string
+	opponents map[string]*Tendencies
+	notes     map[string][]string
+}
+
+// Tendencies are counts of an opponent's observed actions
+type Tendencies struct {
+	HandsSeen     int `json:"handsSeen"`
+	Voluntary     int `json:"voluntary"`     // hands where they called or raised preflop
+	PreflopRaises int `json:"preflopRaises"` // hands where they raised preflop
+	Raises        int `json:"raises"`
+	Calls         int `json:"calls"`
+	Checks        int `json:"checks"`
+	Folds         int `json:"folds"`
+	Showdowns     int `json:"showdowns"`
+	ShowdownsWon  int `json:"showdownsWon"`
+}
+
+// MemoryView is the part of a seat's memory rendered into its prompt
+type MemoryView struct {
+	RecentHands []string       // one-line summaries, oldest first
+	Opponents   []OpponentNote // observed tendencies, by name
+	Notes       []OpponentNote // the model's own notes, by subject
+}
+
+// OpponentNote is a line of text about one opponent (or "general")
+type OpponentNote struct {
+	Name string
+	Text string
+}
+
+// Empty returns true if there is nothing to show yet
+func (m MemoryView) Empty() bool {
+	return len(m.RecentHands) == 0 && len(m.Opponents) == 0 && len(m.Notes) == 0
+}
+
+// NewMemory creates an empty memory for a player, keeping the last
+// handLimit hand summaries
+func NewMemory(self string, handLimit int) *Memory {
+	if handLimit <= 0 {
+		handLimit = defaultMemoryHands
+	}
+	return &Memory{
+		self:      self,
+		limit:     handLimit,
+		opponents: make(map[string]*Tendencies),
+		notes:     make(map[string][]string),
+	}
+}
+
+// ObserveHand updates the memory with a completed hand
+func (m *Memory) ObserveHand(hand models.HandRecord) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.hands = append(m.hands, summarizeHand(m.self, hand))
+	if len(m.hands) > m.limit {
+		m.hands = m.hands[len(m.hands)-m.limit:]
+	}
+
+	seen := make(map[string]bool)
+	voluntary := make(map[string]bool)
+	preflopRaise := make(map[string]bool)
+	for _, action := range hand.Actions {
+		if action.Player == m.self {
+			continue
+		}
+		t := m.opponents[action.Player]
+		if t == nil {
+			t = &Tendencies{}
+			m.opponents[action.Player] = t
+		}
+		seen[action.Player] = true
+
+		switch action.Action {
+		case "raise":
+			t.Raises++
+		case "call":
+			t.Calls++
+		case "check":
+			t.Checks++
+		case "fold":
+			t.Folds++
+		}
+		if action.Round == "preflop" && (action.Action == "call" || action.Action == "raise") {
+			voluntary[action.Player] = true
+			if action.Action == "raise" {
+				preflopRaise[action.Player] = true
+			}
+		}
+	}
+
+	for name := range seen {
+		t := m.opponents[name]
+		t.HandsSeen++
+		if voluntary[name] {
+			t.Voluntary++
+		}
+		if preflopRaise[name] {
+			t.PreflopRaises++
+		}
+		if _, showed := hand.Showdown[name]; showed {
+			t.Showdowns++
+			if hand.Winnings[name] > 0 {
+				t.ShowdownsWon++
+			}
+		}
+	}
+}
+
+// AddNote stores a note about a subject (an opponent's name or "general"),
+// keeping only the most recent few per subject
+func (m *Memory) AddNote(subject, note string) {
+	subject = strings.TrimSpace(subject)
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return
+	}
+	if subject == "" {
+		subject = "general"
+	}
+	if len(note) > maxNoteLength {
+		note = note[:maxNoteLength]
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	notes := append(m.notes[subject], note)
+	if len(notes) > maxNotesPerSubject {
+		notes = notes[len(notes)-maxNotesPerSubject:]
+	}
+	m.notes[subject] = notes
+}
+
+// View renders the memory for inclusion in a prompt
+func (m *Memory) View() MemoryView {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	view := MemoryView{
+		RecentHands: append([]string(nil), m.hands...),
+	}
+
+	for _, name := range sortedKeys(m.opponents) {
+		view.Opponents = append(view.Opponents, OpponentNote{
+			Name: name,
+			Text: m.opponents[name].String(),
+		})
+	}
+	for _, subject := range sortedKeys(m.notes) {
+		for _, note := range m.notes[subject] {
+			view.Notes = append(view.Notes, OpponentNote{Name: subject, Text: note})
+		}
+	}
+
+	return view
+}
+
+// MemoryState is everything a memory holds, for saving a game to carry on
+// with later
+type MemoryState struct {
+	Hands     []string               `json:"hands,omitempty"`
+	Opponents map[string]*Tendencies `json:"opponents,omitempty"`
+	Notes     map[string][]string    `json:"notes,omitempty"`
+}
+
+// State returns a copy of the memory's contents
+func (m *Memory) State() MemoryState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := MemoryState{
+		Hands:     append([]string(nil), m.hands...),
+		Opponents: make(map[string]*Tendencies, len(m.opponents)),
+		Notes:     make(map[string][]string, len(m.notes)),
+	}
+	for name, t := range m.opponents {
+		copied := *t
+		state.Opponents[name] = &copied
+	}
+	for subject, notes := range m.notes {
+		state.Notes[subject] = append([]string(nil), notes...)
+	}
+	return state
+}
+
+// Restore replaces the memory's contents with a saved state
+func (m *Memory) Restore(state MemoryState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.hands = append([]string(nil), state.Hands...)
+	m.opponents = make(map[string]*Tendencies, len(state.Opponents))
+	for name, t := range state.Opponents {
+		copied := *t
+		m.opponents[name] = &copied
+	}
+	m.notes = make(map[string][]string, len(state.Notes))
+	for subject, notes := range state.Notes {
+		m.notes[subject] = append([]string(nil), notes...)
+	}
+}
+
+// String describes the tendencies in one line
+func (t *Tendencies) String() string {
+	if t.HandsSeen == 0 {
+		return "no hands observed"
+	}
+	pct := func(n int) float64 { return float64(n) / float64(t.HandsSeen) * 100 }
+
+	aggression := "n/a"
+	if t.Calls > 0 {
+		aggression = fmt.Sprintf("%.1f", float64(t.Raises)/float64(t.Calls))
+	} else if t.Raises > 0 {
+		aggression = "raises only"
+	}
+
+	return fmt.Sprintf("%d hands seen, played %.0f%% voluntarily, raised preflop %.0f%%, aggression %s, folded %d times, showdowns %d (won %d)",
+		t.HandsSeen, pct(t.Voluntary), pct(t.PreflopRaises), aggression, t.Folds, t.Showdowns, t.ShowdownsWon)
+}
+
+// summarizeHand describes a completed hand in one line from self's view
+func summarizeHand(self string, hand models.HandRecord) string {
+	var actions []string
+	for _, action := range hand.Actions {
+		name := action.Player
+		if name == self {
+			name = "you"
+		}
+		switch action.Action {
+		case "small_blind", "big_blind":
+			continue
+		case "raise":
+			actions = append(actions, fmt.Sprintf("%s raised to $%d (%s)", name, action.TotalBet, action.Round))
+		case "call":
+			actions = append(actions, fmt.Sprintf("%s called $%d (%s)", name, action.Amount, action.Round))
+		default:
+			actions = append(actions, fmt.Sprintf("%s %sed (%s)", name, action.Action, action.Round))
+		}
+	}
+
+	var results []string
+	for _, name := range sortedKeys(hand.Winnings) {
+		winner := name
+		if winner == self {
+			winner = "you"
+		}
+		results = append(results, fmt.Sprintf("%s won $%d", winner, hand.Winnings[name]))
+	}
+
+	summary := strings.TrimSpace(fmt.Sprintf("Hand #%d, board [%s]: %s. %s",
+		hand.HandNumber, strings.Join(hand.Board, " "), strings.Join(actions, ", "), strings.Join(results, ", ")))
+
+	if len(hand.Showdown) > 0 {
+		var shown []string
+		for _, name := range sortedKeys(hand.Showdown) {
+			if name != self {
+				shown = append(shown, fmt.Sprintf("%s showed %s", name, strings.Join(hand.Showdown[name], " ")))
+			}
+		}
+		if len(shown) > 0 {
+			summary += " (" + strings.Join(shown, ", ") + ")"
+		}
+		if hand.WinningHand != "" {
+			summary += " with " + hand.WinningHand
+		}
+	}
+	return summary
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,131 @@
+package ai
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+//go:embed prompts/default.tmpl
+var promptFS embed.FS
+
+// Helper functions available to prompt templates
+var promptFuncs = template.FuncMap{
+	"join":    strings.Join,
+	"cards":   cardsOrNone,
+	"history": formatHistory,
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"sub":     func(a, b int) int { return a - b },
+	"add":     func(a, b int) int { return a + b },
+}
+
+var (
+	defaultPrompt = template.Must(
+		template.New("default.tmpl").Funcs(promptFuncs).ParseFS(promptFS, "prompts/default.tmpl"))
+
+	promptCache   = make(map[string]*template.Template)
+	promptCacheMu sync.Mutex
+)
+
+// LoadPromptTemplate parses a prompt template file, caching it by path.
+// An empty path returns the built-in default prompt.
+func LoadPromptTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return defaultPrompt, nil
+	}
+
+	promptCacheMu.Lock()
+	defer promptCacheMu.Unlock()
+
+	if tmpl, ok := promptCache[path]; ok {
+		return tmpl, nil
+	}
+
+	// ParseFiles names the template after the file's base name
+	tmpl, err := template.New(filepath.Base(path)).Funcs(promptFuncs).ParseFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt template %s: %w", path, err)
+	}
+	promptCache[path] = tmpl
+	return tmpl, nil
+}
+
+// ValidatePromptTemplates parses every seat's template and renders it
+// against an empty view, so mistakes surface before any game starts
+func ValidatePromptTemplates(seats []models.SeatConfig) error {
+	for _, seat := range seats {
+		tmpl, err := LoadPromptTemplate(seat.PromptTemplate)
+		if err != nil {
+			return err
+		}
+		if _, err := RenderPrompt(tmpl, GameView{PlayerName: seat.Name}); err != nil {
+			return fmt.Errorf("prompt template for %s: %w", seat.Name, err)
+		}
+	}
+	return nil
+}
+
+// RenderPrompt executes a prompt template against a seat's view
+func RenderPrompt(tmpl *template.Template, view GameView) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, view); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// BuildPrompt renders a seat's view of the game with its configured template
+func BuildPrompt(seat models.SeatConfig, view GameView) (string, error) {
+	tmpl, err := LoadPromptTemplate(seat.PromptTemplate)
+	if err != nil {
+		return "", err
+	}
+	return RenderPrompt(tmpl, view)
+}
+
+// formatHistory lists the hand's actions grouped by street
+func formatHistory(view GameView) string {
+	if len(view.History) == 0 {
+		return "(no actions yet)\n"
+	}
+
+	var b strings.Builder
+	street := ""
+	for _, action := range view.History {
+		if action.Round != street {
+			street = action.Round
+			fmt.Fprintf(&b, "%s:\n", strings.ToUpper(street[:1])+street[1:])
+		}
+		name := action.Player
+		if name == view.PlayerName {
+			name += " (you)"
+		}
+		switch action.Action {
+		case "small_blind":
+			fmt.Fprintf(&b, "  %s posts small blind $%d\n", name, action.Amount)
+		case "big_blind":
+			fmt.Fprintf(&b, "  %s posts big blind $%d\n", name, action.Amount)
+		case "raise":
+			fmt.Fprintf(&b, "  %s raises to $%d\n", name, action.TotalBet)
+		case "call":
+			fmt.Fprintf(&b, "  %s calls $%d\n", name, action.Amount)
+		default:
+			fmt.Fprintf(&b, "  %s %ss\n", name, action.Action)
+		}
+	}
+	return b.String()
+}
+
+func cardsOrNone(cards []string) string {
+	if len(cards) == 0 {
+		return "none"
+	}
+	return strings.Join(cards, ", ")
+}
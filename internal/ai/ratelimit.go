@@ -0,0 +1,187 @@
+package ai
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// Rate limiting is per provider: a throttled provider makes its own seats
+// wait, in every game, without affecting anyone else.
+
+const (
+	maxRateLimitRetries = 6
+	baseBackoff         = time.Second
+	maxBackoff          = 60 * time.Second
+)
+
+var (
+	rateLimits  = make(map[string]models.RateLimit)
+	limiters    = make(map[string]*limiter)
+	limitersMu  sync.Mutex
+	backoffRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	backoffMu   sync.Mutex
+)
+
+// ConfigureRateLimits sets the request rates per provider. Providers
+// without an entry are not throttled until they return a 429.
+func ConfigureRateLimits(limits map[string]models.RateLimit) {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	rateLimits = make(map[string]models.RateLimit, len(limits))
+	for provider, limit := range limits {
+		rateLimits[strings.ToLower(provider)] = limit
+	}
+	limiters = make(map[string]*limiter)
+}
+
+// ProviderKey names the provider a seat's requests count against: the host
+// of a custom base URL, otherwise the vendor prefix of the OpenRouter
+// model ("openai" for "openai/gpt-5-nano")
+func ProviderKey(seat models.SeatConfig) string {
+	if seat.IsLocal() {
+		if u, err := url.Parse(seat.BaseURL); err == nil && u.Host != "" {
+			return strings.ToLower(u.Host)
+		}
+		return strings.ToLower(seat.BaseURL)
+	}
+	if vendor, _, found := strings.Cut(seat.Model, "/"); found {
+		return strings.ToLower(vendor)
+	}
+	return "openrouter"
+}
+
+func limiterFor(seat models.SeatConfig) *limiter {
+	key := ProviderKey(seat)
+
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	l, ok := limiters[key]
+	if !ok {
+		l = newLimiter(key, rateLimits[key])
+		limiters[key] = l
+	}
+	return l
+}
+
+// limiter is a token bucket that can also be paused when the provider
+// asks us to back off
+type limiter struct {
+	provider    string
+	mu          sync.Mutex
+	rate        float64 // tokens per second, 0 for unlimited
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newLimiter(provider string, limit models.RateLimit) *limiter {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &limiter{
+		provider: provider,
+		rate:     limit.RequestsPerMinute / 60,
+		burst:    burst,
+		tokens:   burst,
+		last:     time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent or ctx is done
+func (l *limiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		var delay time.Duration
+		if now.Before(l.pausedUntil) {
+			delay = l.pausedUntil.Sub(now)
+		} else if l.rate <= 0 {
+			l.mu.Unlock()
+			return nil
+		} else {
+			l.tokens += now.Sub(l.last).Seconds() * l.rate
+			if l.tokens > l.burst {
+				l.tokens = l.burst
+			}
+			l.last = now
+			if l.tokens >= 1 {
+				l.tokens--
+				l.mu.Unlock()
+				return nil
+			}
+			delay = time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		}
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Pause stops all requests to the provider for at least d
+func (l *limiter) Pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until := time.Now().Add(d)
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+		l.last = until
+	}
+	l.tokens = 0
+}
+
+// retryable reports whether a status code means "try again later"
+func retryable(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay honors a Retry-After header (seconds or HTTP date), falling
+// back to exponential backoff with jitter
+func retryDelay(header string, attempt int) time.Duration {
+	if header != "" {
+		if seconds, err := strconv.Atoi(strings.TrimSpace(header)); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if when, err := http.ParseTime(header); err == nil {
+			if d := time.Until(when); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+
+	d := baseBackoff << attempt
+	if d <= 0 || d > maxBackoff {
+		d = maxBackoff
+	}
+
+	// Jitter between half and the full delay so parallel games don't retry
+	// in lockstep
+	backoffMu.Lock()
+	jitter := time.Duration(backoffRand.Int63n(int64(d)/2 + 1))
+	backoffMu.Unlock()
+	return d/2 + jitter
+}
@@ -0,0 +1,37 @@
+package ai
+
+import (
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// Usage is the cost of one or more model requests
+type Usage struct {
+	Requests         int
+	PromptTokens     int
+	CompletionTokens int
+	Latency          time.Duration // time spent waiting on responses
+
+	// Cost as reported by the provider (OpenRouter's usage.cost), 0 if none
+	ReportedCost float64
+}
+
+// Add accumulates another request's usage
+func (u *Usage) Add(other Usage) {
+	u.Requests += other.Requests
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.Latency += other.Latency
+	u.ReportedCost += other.ReportedCost
+}
+
+// Cost estimates the dollar cost of the usage for a seat. A configured price
+// wins over the provider's reported cost, so runs are comparable even when
+// some providers don't report one.
+func (u Usage) Cost(seat models.SeatConfig) float64 {
+	if seat.Price != nil {
+		return seat.Price.Cost(u.PromptTokens, u.CompletionTokens)
+	}
+	return u.ReportedCost
+}
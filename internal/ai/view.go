@@ -0,0 +1,266 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// GameView is everything one seat is allowed to know when it acts. It is
+// built from the full GameState but never contains other players' cards.
+type GameView struct {
+	PlayerName string   // the acting player
+	HoleCards  []string // the acting player's two cards
+	Position   string   // e.g. "Button", "Small blind", "Under the gun"
+
+	HandNumber int
+	Street     string   // preflop, flop, turn or river
+	Board      []string // community cards dealt so far
+	Pot        int
+	SmallBlind int
+	BigBlind   int
+	Dealer     string // name of the player on the button
+
+	Chips      int  // acting player's remaining stack
+	CurrentBet int  // highest total bet on this street
+	YourBet    int  // acting player's total bet on this street
+	ToCall     int  // chips needed to call
+	CanCheck   bool // true when ToCall is 0
+	MinRaiseTo int  // smallest legal raise, as a total bet
+	MaxRaiseTo int  // largest affordable raise, as a total bet
+
+	// Other players in seat order, starting with the one to our left
+	Opponents []OpponentView
+
+	// Actions taken so far this hand, oldest first, including blinds.
+	// Only what the table saw: no cards, reasoning or thinking.
+	History []models.ActionRecord
+
+	// What the seat remembers from earlier hands (empty unless the seat
+	// has memory or note-taking enabled)
+	Memory MemoryView
+
+	// True if the seat may save notes for later hands
+	NoteTaking bool
+}
+
+// OpponentView is the public information about another seat
+type OpponentView struct {
+	Name       string
+	Position   string
+	Chips      int
+	Bet        int // total bet on this street
+	Folded     bool
+	Eliminated bool
+}
+
+// InHand returns the number of players still contesting the pot
+func (v GameView) InHand() int {
+	count := 1
+	for _, opp := range v.Opponents {
+		if !opp.Folded && !opp.Eliminated {
+			count++
+		}
+	}
+	return count
+}
+
+// BuildGameView extracts the acting player's view of the game state
+func BuildGameView(player models.Player, state *models.GameState) GameView {
+	yourBet := state.PlayerBets[player.Name]
+	positions := seatPositions(state)
+
+	view := GameView{
+		PlayerName: player.Name,
+		HoleCards:  append([]string(nil), player.Cards...),
+		Position:   positions[player.Name],
+		HandNumber: state.HandNumber,
+		Street:     state.Round,
+		Board:      append([]string(nil), state.CommunityCards...),
+		Pot:        state.Pot,
+		SmallBlind: state.SmallBlind,
+		BigBlind:   state.BigBlind,
+		Chips:      player.Chips,
+		CurrentBet: state.CurrentBet,
+		YourBet:    yourBet,
+		ToCall:     state.CurrentBet - yourBet,
+		MinRaiseTo: state.CurrentBet + state.MinRaise,
+		MaxRaiseTo: yourBet + player.Chips,
+		History:    publicHistory(state.HandActions),
+	}
+	view.CanCheck = view.ToCall == 0
+	if state.DealerPosition < len(state.Players) {
+		view.Dealer = state.Players[state.DealerPosition].Name
+	}
+
+	self := -1
+	for i, p := range state.Players {
+		if p.Name == player.Name {
+			self = i
+			break
+		}
+	}
+	for i := 1; i < len(state.Players); i++ {
+		p := state.Players[(self+i+len(state.Players))%len(state.Players)]
+		view.Opponents = append(view.Opponents, OpponentView{
+			Name:       p.Name,
+			Position:   positions[p.Name],
+			Chips:      p.Chips,
+			Bet:        state.PlayerBets[p.Name],
+			Folded:     contains(state.FoldedPlayers, p.Name),
+			Eliminated: contains(state.EliminatedPlayers, p.Name),
+		})
+	}
+
+	return view
+}
+
+// publicHistory copies the hand's actions without what only the player who
+// took them knows: their cards and their model's reasoning
+func publicHistory(actions []models.ActionRecord) []models.ActionRecord {
+	history := make([]models.ActionRecord, len(actions))
+	for i, action := range actions {
+		history[i] = models.ActionRecord{
+			HandNumber: action.HandNumber,
+			Round:      action.Round,
+			Player:     action.Player,
+			Action:     action.Action,
+			Amount:     action.Amount,
+			TotalBet:   action.TotalBet,
+		}
+	}
+	return history
+}
+
+// seatPositions names each active player's position relative to the button.
+// Blinds are taken from the hand history, since that's who actually posted.
+func seatPositions(state *models.GameState) map[string]string {
+	positions := make(map[string]string)
+	n := len(state.Players)
+	if n == 0 {
+		return positions
+	}
+
+	var order []string
+	for i := 0; i < n; i++ {
+		p := state.Players[(state.DealerPosition+i)%n]
+		if !contains(state.EliminatedPlayers, p.Name) {
+			order = append(order, p.Name)
+		}
+	}
+	if len(order) == 0 {
+		return positions
+	}
+
+	for _, action := range state.HandActions {
+		switch action.Action {
+		case "small_blind":
+			positions[action.Player] = "Small blind"
+		case "big_blind":
+			positions[action.Player] = "Big blind"
+		}
+	}
+
+	// Seats after the big blind, in acting order
+	var rest []string
+	for _, name := range order[1:] {
+		if _, isBlind := positions[name]; !isBlind {
+			rest = append(rest, name)
+		}
+	}
+	if blind, isBlind := positions[order[0]]; isBlind {
+		positions[order[0]] = "Button / " + blind
+	} else {
+		positions[order[0]] = "Button"
+	}
+	for i, name := range rest {
+		switch {
+		case i == 0:
+			positions[name] = "Under the gun"
+		case i == len(rest)-1:
+			positions[name] = "Cutoff"
+		default:
+			positions[name] = "Middle position"
+		}
+	}
+
+	return positions
+}
+
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+// LegalActions lists the actions the engine will accept without coercion
+func (v GameView) LegalActions() []string {
+	actions := []string{"fold"}
+	if v.CanCheck {
+		actions = append(actions, "check")
+	} else if v.Chips >= v.ToCall {
+		actions = append(actions, "call")
+	}
+	if v.MaxRaiseTo >= v.MinRaiseTo {
+		actions = append(actions, "raise")
+	}
+	return actions
+}
+
+// DescribeLegalActions explains the legal actions in one line, for error
+// feedback to the model
+func (v GameView) DescribeLegalActions() string {
+	var parts []string
+	for _, action := range v.LegalActions() {
+		switch action {
+		case "call":
+			parts = append(parts, fmt.Sprintf("call ($%d)", v.ToCall))
+		case "raise":
+			parts = append(parts, fmt.Sprintf("raise (to a total between $%d and $%d)", v.MinRaiseTo, v.MaxRaiseTo))
+		default:
+			parts = append(parts, action)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ValidateAction checks an action against the legal action set, returning
+// an error that explains the problem in terms the model can act on
+func ValidateAction(args ActionArgs, view GameView) error {
+	switch args.Action {
+	case "fold":
+		return nil
+	case "check":
+		if !view.CanCheck {
+			return fmt.Errorf("cannot check when facing a bet, amount to call is $%d", view.ToCall)
+		}
+		return nil
+	case "call":
+		if view.Chips < view.ToCall {
+			return fmt.Errorf("cannot call $%d with only $%d in chips", view.ToCall, view.Chips)
+		}
+		return nil
+	case "raise":
+		if view.MaxRaiseTo < view.MinRaiseTo {
+			return fmt.Errorf("cannot raise, the minimum raise to $%d needs more than your $%d in chips", view.MinRaiseTo, view.Chips)
+		}
+		if args.RaiseAmount <= 0 {
+			return fmt.Errorf("raise needs a raise_amount (total bet between $%d and $%d)", view.MinRaiseTo, view.MaxRaiseTo)
+		}
+		if args.RaiseAmount < view.MinRaiseTo {
+			return fmt.Errorf("raise to $%d is below the minimum of $%d", args.RaiseAmount, view.MinRaiseTo)
+		}
+		if args.RaiseAmount > view.MaxRaiseTo {
+			return fmt.Errorf("raise to $%d is more than you can afford, the maximum is $%d", args.RaiseAmount, view.MaxRaiseTo)
+		}
+		return nil
+	case "":
+		return fmt.Errorf("no action given")
+	default:
+		return fmt.Errorf("unknown action %q", args.Action)
+	}
+}
@@ -0,0 +1,41 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+func TestBuildGameViewHidesPrivateActionFields(t *testing.T) {
+	state := &models.GameState{
+		Players: []models.Player{
+			{Name: "alice", Chips: 90, Cards: []string{"A♠", "K♠"}},
+			{Name: "bob", Chips: 80, Cards: []string{"7♦", "2♣"}},
+		},
+		PlayerBets: map[string]int{"alice": 10, "bob": 20},
+		HandActions: []models.ActionRecord{
+			{HandNumber: 1, Round: "preflop", Player: "bob", Action: "raise", Amount: 20, TotalBet: 20,
+				Cards: []string{"7♦", "2♣"}, Reasoning: "bluffing", Thinking: "weak hand"},
+		},
+		HandNumber: 1,
+		Round:      "preflop",
+		CurrentBet: 20,
+		BigBlind:   10,
+	}
+
+	view := BuildGameView(state.Players[0], state)
+	if len(view.History) != 1 {
+		t.Fatalf("history has %d actions, want 1", len(view.History))
+	}
+	got := view.History[0]
+	want := models.ActionRecord{HandNumber: 1, Round: "preflop", Player: "bob", Action: "raise", Amount: 20, TotalBet: 20}
+	if got.Cards != nil || got.Reasoning != "" || got.Thinking != "" {
+		t.Errorf("history leaks private fields: %+v", got)
+	}
+	if got.Player != want.Player || got.Action != want.Action || got.Amount != want.Amount || got.TotalBet != want.TotalBet || got.Round != want.Round {
+		t.Errorf("history = %+v, want %+v", got, want)
+	}
+	if state.HandActions[0].Reasoning != "bluffing" {
+		t.Errorf("building the view changed the game state")
+	}
+}
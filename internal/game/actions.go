@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/MikeLuu99/poker-arena/internal/ai"
 	"github.com/MikeLuu99/poker-arena/internal/poker"
 	"github.com/MikeLuu99/poker-arena/pkg/models"
 )
@@ -53,6 +54,7 @@ func (g *Game) postBlinds() {
 			g.State.Players[i].Chips -= sbAmount
 			g.State.Pot += sbAmount
 			g.State.PlayerBets[g.State.Players[i].Name] = sbAmount
+			g.recordAction(g.State.Players[i].Name, "small_blind", sbAmount)
 			g.addToLog(fmt.Sprintf("%s posts small blind $%d", g.State.Players[i].Name, sbAmount))
 		}
 
@@ -62,6 +64,7 @@ func (g *Game) postBlinds() {
 			g.State.Pot += bbAmount
 			g.State.PlayerBets[g.State.Players[i].Name] = bbAmount
 			g.State.CurrentBet = bbAmount
+			g.recordAction(g.State.Players[i].Name, "big_blind", bbAmount)
 			g.addToLog(fmt.Sprintf("%s posts big blind $%d", g.State.Players[i].Name, bbAmount))
 		}
 	}
@@ -101,6 +104,7 @@ func (g *Game) processDecision(decision string, playerIndex int) {
 			g.State.Pot += actualRaiseAmount
 			player.Chips -= actualRaiseAmount
 			g.State.PlayerBets[player.Name] = totalBet
+			g.recordAction(player.Name, "raise", actualRaiseAmount)
 			g.addToLog(fmt.Sprintf("%s raises to $%d (adding $%d)", player.Name, totalBet, actualRaiseAmount))
 		} else {
 			// If player can't afford raise, convert to call if possible
@@ -115,12 +119,14 @@ func (g *Game) processDecision(decision string, playerIndex int) {
 			g.State.Pot += amountToCall
 			player.Chips -= amountToCall
 			g.State.PlayerBets[player.Name] = g.State.CurrentBet
+			g.recordAction(player.Name, "call", amountToCall)
 			g.addToLog(fmt.Sprintf("%s calls $%d", player.Name, amountToCall))
 		} else {
 			g.processDecision("fold", playerIndex)
 		}
 	} else if decision == "check" {
 		if amountToCall == 0 {
+			g.recordAction(player.Name, "check", 0)
 			g.addToLog(fmt.Sprintf("%s checks", player.Name))
 		} else {
 			// Invalid check - convert to call or fold
@@ -131,11 +137,51 @@ func (g *Game) processDecision(decision string, playerIndex int) {
 			}
 		}
 	} else {
+		g.recordAction(player.Name, "fold", 0)
 		g.addToLog(fmt.Sprintf("%s folds", player.Name))
 		g.State.FoldedPlayers = append(g.State.FoldedPlayers, player.Name)
 	}
 }
 
+// recordHand keeps a finished hand for the game result and shares it with
+// every seat's agent
+func (g *Game) recordHand(hand models.HandRecord) {
+	g.handsMu.Lock()
+	g.hands = append(g.hands, hand)
+	g.handsMu.Unlock()
+	for _, agent := range g.agents {
+		agent.ObserveHand(hand)
+	}
+}
+
+// recordAction appends an action to the current hand's betting history
+func (g *Game) recordAction(playerName, action string, amount int) {
+	g.State.HandActions = append(g.State.HandActions, models.ActionRecord{
+		HandNumber: g.State.HandNumber,
+		Round:      g.State.Round,
+		Player:     playerName,
+		Action:     action,
+		Amount:     amount,
+		TotalBet:   g.State.PlayerBets[playerName],
+	})
+}
+
+// annotateLastAction attaches the player's cards and the model's reasoning
+// to the action their decision produced
+func (g *Game) annotateLastAction(player models.Player, decision ai.Decision) {
+	if len(g.State.HandActions) == 0 {
+		return
+	}
+	last := &g.State.HandActions[len(g.State.HandActions)-1]
+	if last.Player != player.Name {
+		return
+	}
+	last.Cards = append([]string(nil), player.Cards...)
+	last.Reasoning = decision.Reasoning
+	last.Thinking = decision.Thinking
+	last.Fallback = decision.Fallback
+}
+
 func (g *Game) findFirstActivePlayerAfterDealer() int {
 	for i := 1; i <= len(g.State.Players); i++ {
 		nextPos := (g.State.DealerPosition + i) % len(g.State.Players)
@@ -229,11 +275,21 @@ func (g *Game) isBettingRoundComplete() bool {
 func (g *Game) endHand() {
 	var activePlayers []models.Player
 	for _, player := range g.State.Players {
-		if !contains(g.State.FoldedPlayers, player.Name) {
+		if !contains(g.State.FoldedPlayers, player.Name) &&
+			!contains(g.State.EliminatedPlayers, player.Name) {
 			activePlayers = append(activePlayers, player)
 		}
 	}
 
+	hand := models.HandRecord{
+		HandNumber: g.State.HandNumber,
+		Board:      append([]string(nil), g.State.CommunityCards...),
+		Actions:    append([]models.ActionRecord(nil), g.State.HandActions...),
+		Pot:        g.State.Pot,
+		BigBlind:   g.State.BigBlind,
+		Winnings:   make(map[string]int),
+	}
+
 	// If only one player remains, they win by default
 	if len(activePlayers) == 1 {
 		winner := &activePlayers[0]
@@ -241,6 +297,7 @@ func (g *Game) endHand() {
 		for i := range g.State.Players {
 			if g.State.Players[i].Name == winner.Name {
 				g.State.Players[i].Chips += g.State.Pot
+				hand.Winnings[winner.Name] += g.State.Pot
 				g.addToLog(fmt.Sprintf("%s wins pot of $%d (all others folded)", winner.Name, g.State.Pot))
 				break
 			}
@@ -250,9 +307,15 @@ func (g *Game) endHand() {
 		bigBlindPos := (g.State.DealerPosition + 2) % len(g.State.Players)
 		winner := &g.State.Players[bigBlindPos]
 		winner.Chips += g.State.Pot
+		hand.Winnings[winner.Name] += g.State.Pot
 		g.addToLog(fmt.Sprintf("%s wins pot of $%d (all players folded, awarded to big blind)", winner.Name, g.State.Pot))
 	} else {
 		// Multiple players remain, compare hands
+		hand.Showdown = make(map[string][]string)
+		for _, player := range activePlayers {
+			hand.Showdown[player.Name] = append([]string(nil), player.Cards...)
+		}
+
 		// Only compare hands if we have all 5 community cards
 		if len(g.State.CommunityCards) < 5 {
 			g.addToLog(fmt.Sprintf("Hand ended early with %d community cards - pot split among remaining players", len(g.State.CommunityCards)))
@@ -267,6 +330,7 @@ func (g *Game) endHand() {
 							share++ // Distribute remainder
 						}
 						g.State.Players[j].Chips += share
+						hand.Winnings[player.Name] += share
 						g.addToLog(fmt.Sprintf("%s receives $%d from split pot", player.Name, share))
 						break
 					}
@@ -289,6 +353,8 @@ func (g *Game) endHand() {
 						playerCards := strings.Join(append(g.State.Players[i].Cards, g.State.CommunityCards...), "")
 						if playerCards == winningCards {
 							g.State.Players[i].Chips += g.State.Pot
+							hand.Winnings[g.State.Players[i].Name] += g.State.Pot
+							hand.WinningHand = winningHand.GetHandName()
 							g.addToLog(fmt.Sprintf("%s wins pot of $%d with %s", g.State.Players[i].Name, g.State.Pot, winningHand.GetHandName()))
 							break
 						}
@@ -305,9 +371,19 @@ func (g *Game) endHand() {
 	}
 	g.addToLog(fmt.Sprintf("Hand #%d complete. Balances: %s", g.State.HandNumber, strings.Join(balances, ", ")))
 
+	if g.cash != nil {
+		g.topUp()
+	}
+
 	// Check for eliminations and tournament end
-	g.checkForEliminations()
-	g.checkForTournamentEnd()
+	hand.Eliminated = g.checkForEliminations()
+	g.recordHand(hand)
+	if g.betweenHands != nil {
+		g.betweenHands(g)
+	}
+	if !g.State.GameEnded {
+		g.checkForTournamentEnd()
+	}
 
 	// Reset for next hand
 	g.State.Pot = 0
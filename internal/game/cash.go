@@ -0,0 +1,125 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// defaultCashBuyIn is the starting stack of a cash game in big blinds
+const defaultCashBuyIn = 100
+
+// SetCashGame plays the game as a cash game instead of a freeze-out: every
+// player buys in for the same stack, short stacks are topped up between
+// hands if rebuys are on, and the game ends after a set number of hands or
+// amount of time. Players are ranked by the chips they won or lost.
+func (g *Game) SetCashGame(cash *models.CashConfig) {
+	if cash == nil {
+		g.cash = nil
+		return
+	}
+
+	c := *cash
+	if c.BuyIn <= 0 {
+		c.BuyIn = defaultCashBuyIn * g.State.BigBlind
+	}
+	if c.TopUpTo <= 0 {
+		c.TopUpTo = c.BuyIn
+	}
+	g.cash = &c
+
+	for i := range g.State.Players {
+		g.State.Players[i].Chips = c.BuyIn
+		g.stats[g.State.Players[i].Name].BuyIn = c.BuyIn
+	}
+	g.expectedChips = len(g.State.Players) * c.BuyIn
+}
+
+// topUp buys more chips for players whose stacks fell below the top-up
+// threshold, or busted when there is none
+func (g *Game) topUp() {
+	if !g.cash.Rebuy {
+		return
+	}
+
+	for i := range g.State.Players {
+		player := &g.State.Players[i]
+		if contains(g.State.EliminatedPlayers, player.Name) {
+			continue
+		}
+		if player.Chips > 0 && player.Chips >= g.cash.TopUpBelow {
+			continue
+		}
+		amount := g.cash.TopUpTo - player.Chips
+		if amount <= 0 {
+			continue
+		}
+
+		player.Chips += amount
+		g.expectedChips += amount
+		stats := g.stats[player.Name]
+		stats.BuyIn += amount
+		stats.Rebuys++
+		g.addToLog(fmt.Sprintf("💵 %s tops up $%d to $%d", player.Name, amount, player.Chips))
+	}
+}
+
+// checkForCashGameEnd ends a cash game once it has played its hands or run
+// its time, or when fewer than two players have chips left
+func (g *Game) checkForCashGameEnd() bool {
+	played := len(g.hands)
+	duration := time.Since(g.startTime)
+
+	var reason string
+	switch {
+	case g.cash.Hands > 0 && played >= g.cash.Hands:
+		reason = fmt.Sprintf("%d hands played", played)
+	case g.cash.DurationSeconds > 0 && duration.Seconds() >= g.cash.DurationSeconds:
+		reason = "time is up"
+	case len(g.getActivePlayers()) < 2:
+		reason = "not enough players with chips"
+	default:
+		return false
+	}
+
+	g.State.GameEnded = true
+	g.result = g.newResult(models.Player{})
+	g.result.PlayerRankings = cashRankings(g.State.Players, g.stats)
+	if len(g.result.PlayerRankings) > 0 {
+		g.result.Winner = g.result.PlayerRankings[0].Player
+		g.result.FinalChips = g.result.Winner.Chips
+	}
+
+	winner := g.result.Winner.Name
+	net := g.stats[winner].NetChips
+	g.addToLog(fmt.Sprintf("💵 CASH GAME OVER (%s): %s is up $%d 💵", reason, winner, net))
+	log.Printf("💵 Cash game ended (%s)! Biggest winner: %s %+d chips in %d hands (Duration: %v)",
+		reason, winner, net, played, duration)
+	return true
+}
+
+// cashRankings orders players by net chips, best first. Players with the
+// same result share a rank.
+func cashRankings(players []models.Player, stats map[string]*models.PlayerGameStats) []models.PlayerRanking {
+	ranked := append([]models.Player(nil), players...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return stats[ranked[i].Name].NetChips > stats[ranked[j].Name].NetChips
+	})
+
+	rankings := make([]models.PlayerRanking, len(ranked))
+	for i, player := range ranked {
+		rank := i + 1
+		if i > 0 && stats[player.Name].NetChips == stats[ranked[i-1].Name].NetChips {
+			rank = rankings[i-1].Rank
+		}
+		rankings[i] = models.PlayerRanking{
+			Player:   player,
+			Rank:     rank,
+			Position: fmt.Sprintf("%+d chips", stats[player.Name].NetChips),
+		}
+	}
+	return rankings
+}
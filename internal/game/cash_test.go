@@ -0,0 +1,94 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+func TestCashGameBuysInDeep(t *testing.T) {
+	g := NewGameWithSeats(1, []models.SeatConfig{{Name: "a"}, {Name: "b"}})
+	g.SetCashGame(&models.CashConfig{Hands: 10})
+	for _, player := range g.State.Players {
+		if player.Chips != 100*g.State.BigBlind || g.stats[player.Name].BuyIn != player.Chips {
+			t.Errorf("%s bought in for %d chips, want 100 big blinds", player.Name, player.Chips)
+		}
+	}
+	if g.cash.TopUpTo != g.cash.BuyIn || g.expectedChips != 2*g.cash.BuyIn {
+		t.Errorf("top-up to %d with %d chips in play, want the buy-in and both stacks", g.cash.TopUpTo, g.expectedChips)
+	}
+}
+
+func TestTopUp(t *testing.T) {
+	seats := []models.SeatConfig{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}}
+	tests := []struct {
+		name       string
+		cash       models.CashConfig
+		wantChips  []int
+		wantRebuys []int
+	}{
+		{"no rebuys", models.CashConfig{BuyIn: 1000}, []int{0, 300, 2700, 0}, []int{0, 0, 0, 0}},
+		{"busted stacks only", models.CashConfig{BuyIn: 1000, Rebuy: true}, []int{1000, 300, 2700, 0}, []int{1, 0, 0, 0}},
+		{"below threshold", models.CashConfig{BuyIn: 1000, Rebuy: true, TopUpBelow: 500}, []int{1000, 1000, 2700, 0}, []int{1, 1, 0, 0}},
+		{"to a bigger stack", models.CashConfig{BuyIn: 1000, Rebuy: true, TopUpBelow: 500, TopUpTo: 2000}, []int{2000, 2000, 2700, 0}, []int{1, 1, 0, 0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGameWithSeats(1, seats)
+			g.SetCashGame(&test.cash)
+			// d has left the game and is never topped up
+			for i, chips := range []int{0, 300, 2700, 0} {
+				g.State.Players[i].Chips = chips
+			}
+			g.State.EliminatedPlayers = []string{"d"}
+			g.expectedChips = 3000
+
+			g.topUp()
+
+			added := 0
+			for i, player := range g.State.Players {
+				stats := g.stats[player.Name]
+				if player.Chips != test.wantChips[i] || stats.Rebuys != test.wantRebuys[i] {
+					t.Errorf("%s has %d chips after %d rebuys, want %d after %d",
+						player.Name, player.Chips, stats.Rebuys, test.wantChips[i], test.wantRebuys[i])
+				}
+				added += stats.BuyIn - test.cash.BuyIn
+			}
+			if g.expectedChips != 3000+added {
+				t.Errorf("table expects %d chips after %d were bought, want %d", g.expectedChips, added, 3000+added)
+			}
+		})
+	}
+}
+
+func TestCashRankings(t *testing.T) {
+	players := []models.Player{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}}
+	stats := map[string]*models.PlayerGameStats{
+		"a": {NetChips: -400},
+		"b": {NetChips: 250},
+		"c": {NetChips: 250},
+		"d": {NetChips: -100},
+	}
+
+	want := []struct {
+		name     string
+		rank     int
+		position string
+	}{
+		{"b", 1, "+250 chips"},
+		{"c", 1, "+250 chips"},
+		{"d", 3, "-100 chips"},
+		{"a", 4, "-400 chips"},
+	}
+	rankings := cashRankings(players, stats)
+	if len(rankings) != len(want) {
+		t.Fatalf("%d rankings, want %d", len(rankings), len(want))
+	}
+	for i, w := range want {
+		got := rankings[i]
+		if got.Player.Name != w.name || got.Rank != w.rank || got.Position != w.position {
+			t.Errorf("ranking %d = %s rank %d %q, want %s rank %d %q",
+				i+1, got.Player.Name, got.Rank, got.Position, w.name, w.rank, w.position)
+		}
+	}
+}
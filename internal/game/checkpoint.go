@@ -0,0 +1,145 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/internal/ai"
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// Snapshot is a game saved at the start of a hand, before the cards are
+// dealt, with everything needed to carry on from there later: the table,
+// the hands played so far, every player's statistics, time bank and
+// memory, and how far the deck's and bots' RNGs have got
+type Snapshot struct {
+	ID            int                                `json:"id"`
+	State         models.GameState                   `json:"state"`
+	Seats         []models.SeatConfig                `json:"seats"`
+	Stats         map[string]*models.PlayerGameStats `json:"stats"`
+	Hands         []models.HandRecord                `json:"hands"`
+	Memories      map[string]ai.MemoryState          `json:"memories,omitempty"`
+	TimeBanks     map[string]time.Duration           `json:"timeBanks"`
+	ExpectedChips int                                `json:"expectedChips"`
+	Cash          *models.CashConfig                 `json:"cash,omitempty"`
+	FallbackUsed  bool                               `json:"fallbackUsed,omitempty"`
+	Seed          int64                              `json:"seed"`
+	Draws         uint64                             `json:"draws"`              // values drawn from the seeded RNG so far
+	BotDraws      map[string]uint64                  `json:"botDraws,omitempty"` // values drawn by each seat's random bot
+	Elapsed       time.Duration                      `json:"elapsed"`
+}
+
+// SetCheckpoint sets a function to receive a snapshot of the game at the
+// start of every hand. It runs on the game's goroutine, and the snapshot
+// shares nothing with the running game.
+func (g *Game) SetCheckpoint(fn func(*Snapshot)) {
+	g.checkpoint = fn
+}
+
+// snapshot copies the game between hands
+func (g *Game) snapshot() *Snapshot {
+	g.handsMu.Lock()
+	live := &Snapshot{
+		ID:            g.ID,
+		State:         *g.State,
+		Seats:         g.seats,
+		Stats:         g.stats,
+		Hands:         g.hands,
+		Memories:      make(map[string]ai.MemoryState),
+		TimeBanks:     g.timeBanks,
+		ExpectedChips: g.expectedChips,
+		Cash:          g.cash,
+		FallbackUsed:  g.fallbackUsed,
+		Seed:          g.seed,
+		Elapsed:       time.Since(g.startTime),
+	}
+	for name, agent := range g.agents {
+		if agent.Memory != nil {
+			live.Memories[name] = agent.Memory.State()
+		}
+		if draws := agent.BotDraws(); draws > 0 {
+			if live.BotDraws == nil {
+				live.BotDraws = make(map[string]uint64)
+			}
+			live.BotDraws[name] = draws
+		}
+	}
+	if g.source != nil {
+		live.Draws = g.source.draws
+	}
+
+	// A round trip through JSON is a deep copy, and checks the snapshot
+	// will save
+	data, err := json.Marshal(live)
+	g.handsMu.Unlock()
+	snapshot := &Snapshot{}
+	if err == nil {
+		err = json.Unmarshal(data, snapshot)
+	}
+	if err != nil {
+		log.Printf("Warning: game %d snapshot failed: %v", g.ID, err)
+		return nil
+	}
+	return snapshot
+}
+
+// Restore carries on a game from a snapshot. Call it on a game created with
+// the snapshot's seats, after the rest of its setup: it replaces the
+// table, statistics, memories, time banks, cash game settings, deck and
+// random bots.
+func (g *Game) Restore(snapshot *Snapshot) {
+	state := snapshot.State
+	g.State = &state
+	g.stats = snapshot.Stats
+	g.hands = snapshot.Hands
+	for name, memory := range snapshot.Memories {
+		if agent, ok := g.agents[name]; ok && agent.Memory != nil {
+			agent.Memory.Restore(memory)
+		}
+	}
+	for name, bank := range snapshot.TimeBanks {
+		g.timeBanks[name] = bank
+	}
+	g.expectedChips = snapshot.ExpectedChips
+	g.cash = snapshot.Cash
+	g.fallbackUsed = snapshot.FallbackUsed
+	g.startTime = time.Now().Add(-snapshot.Elapsed)
+
+	g.SetSeed(snapshot.Seed)
+	if g.source != nil {
+		for g.source.draws < snapshot.Draws {
+			g.source.Uint64()
+		}
+	}
+	for name, draws := range snapshot.BotDraws {
+		if agent, ok := g.agents[name]; ok {
+			agent.SeedBot(snapshot.Seed, draws)
+		}
+	}
+	g.addToLog(fmt.Sprintf("Game resumed at hand #%d", g.State.HandNumber))
+}
+
+// countingSource is a seeded RNG source that counts its draws, so a
+// restored game can deal the decks it would have dealt
+type countingSource struct {
+	source rand.Source64
+	draws  uint64
+}
+
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.source.Int63()
+}
+
+func (s *countingSource) Uint64() uint64 {
+	s.draws++
+	return s.source.Uint64()
+}
+
+func (s *countingSource) Seed(seed int64) {
+	s.source.Seed(seed)
+	s.draws = 0
+}
@@ -1,9 +1,13 @@
 package game
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math/rand"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/MikeLuu99/poker-arena/internal/ai"
@@ -12,11 +16,35 @@ import (
 )
 
 type Game struct {
-	ID        int
-	State     *models.GameState
-	stopChan  chan bool
-	result    *models.GameResult
-	startTime time.Time
+	ID     int
+	State  *models.GameState
+	agents map[string]*ai.Agent
+	stats  map[string]*models.PlayerGameStats
+	hands  []models.HandRecord
+	seats  []models.SeatConfig
+
+	handsMu sync.Mutex // hands is read by the web server while the game runs
+
+	fallbackUsed bool // a fallback agent decided for some seat
+	stopChan     chan bool
+	stopOnce     sync.Once
+	result       *models.GameResult
+	startTime    time.Time
+
+	rng         *rand.Rand      // deck shuffling, nil for an unseeded deck
+	seed        int64           // the deck seed, 0 for an unseeded deck
+	source      *countingSource // rng's source, counting draws for snapshots
+	actionDelay time.Duration   // pause between actions so the web UI can follow
+
+	// Per-player time limit for each decision (0 for none) and remaining
+	// time bank
+	actionTimeouts map[string]time.Duration
+	timeBanks      map[string]time.Duration
+
+	expectedChips int                // chips on the table, changed only by players joining, leaving or topping up
+	betweenHands  func(g *Game)      // seat changes between hands, for multi-table play
+	cash          *models.CashConfig // cash game settings, nil for a freeze-out
+	checkpoint    func(*Snapshot)    // saves the game at the start of every hand
 }
 
 var models_list = []string{
@@ -26,21 +54,51 @@ var models_list = []string{
 	"anthropic/claude-3.5-haiku",
 }
 
-var initialTotalChips *int
+// Every player starts a game with this many chips
+const startingChips = 20
+
+// DefaultActionDelay is the pause between actions
+const DefaultActionDelay = 2 * time.Second
+
+// DefaultActionTimeout is the time a player has for each decision
+const DefaultActionTimeout = 30 * time.Second
 
 func NewGame() *Game {
 	return NewGameWithID(1)
 }
 
 func NewGameWithID(gameID int) *Game {
-	players := make([]models.Player, len(models_list))
+	return NewGameWithSeats(gameID, nil)
+}
+
+// DefaultSeats returns the built-in OpenRouter lineup
+func DefaultSeats() []models.SeatConfig {
+	seats := make([]models.SeatConfig, len(models_list))
 	for i, model := range models_list {
+		seats[i] = models.SeatConfig{Name: model, Model: model}
+	}
+	return seats
+}
+
+// NewGameWithSeats creates a game with the given seats, or the default
+// lineup if seats is empty
+func NewGameWithSeats(gameID int, seats []models.SeatConfig) *Game {
+	if len(seats) == 0 {
+		seats = DefaultSeats()
+	}
+
+	players := make([]models.Player, len(seats))
+	agents := make(map[string]*ai.Agent, len(seats))
+	stats := make(map[string]*models.PlayerGameStats, len(seats))
+	for i, seat := range seats {
 		players[i] = models.Player{
-			Name:  model,
-			Chips: 20,
+			Name:  seat.Name,
+			Chips: startingChips,
 			Cards: []string{},
-			Model: model,
+			Model: seat.Model,
 		}
+		agents[seat.Name] = ai.NewAgent(seat)
+		stats[seat.Name] = &models.PlayerGameStats{BuyIn: startingChips}
 	}
 
 	gameState := &models.GameState{
@@ -58,22 +116,73 @@ func NewGameWithID(gameID int) *Game {
 		MinRaise:          10,
 		FoldedPlayers:     []string{},
 		DealerPosition:    0,
-		SmallBlind:        5,
-		BigBlind:          10,
+		SmallBlind:        models.SmallBlind,
+		BigBlind:          models.BigBlind,
 		BettingComplete:   false,
 		EliminatedPlayers: []string{},
 		GameEnded:         false,
+		HandActions:       []models.ActionRecord{},
+	}
+
+	g := &Game{
+		ID:            gameID,
+		State:         gameState,
+		agents:        agents,
+		stats:         stats,
+		seats:         seats,
+		stopChan:      make(chan bool),
+		expectedChips: len(seats) * startingChips,
+		result:        nil,
+		startTime:     time.Now(),
+		actionDelay:   DefaultActionDelay,
+	}
+	g.SetTimeControl(DefaultActionTimeout, 0)
+	return g
+}
+
+// SetSeed makes the deck order and the random baseline bot reproducible:
+// games with the same seed deal the same cards. A seed of 0 keeps the deck
+// random.
+func (g *Game) SetSeed(seed int64) {
+	g.seed = seed
+	for _, agent := range g.agents {
+		agent.SeedBot(seed, 0)
+	}
+	if seed == 0 {
+		g.rng, g.source = nil, nil
+		return
 	}
+	g.source = &countingSource{source: rand.NewSource(seed).(rand.Source64)}
+	g.rng = rand.New(g.source)
+}
 
-	return &Game{
-		ID:        gameID,
-		State:     gameState,
-		stopChan:  make(chan bool),
-		result:    nil,
-		startTime: time.Now(),
+// SetTimeControl sets every player's time limit per decision (0 for none)
+// and their time bank for the game: extra time a decision can use once the
+// limit runs out. Seats with their own limits keep them.
+func (g *Game) SetTimeControl(actionTimeout, timeBank time.Duration) {
+	g.actionTimeouts = make(map[string]time.Duration, len(g.agents))
+	g.timeBanks = make(map[string]time.Duration, len(g.agents))
+	for name, agent := range g.agents {
+		timeout, bank := actionTimeout, timeBank
+		if agent.Seat.ActionTimeoutSeconds > 0 {
+			timeout = time.Duration(agent.Seat.ActionTimeoutSeconds * float64(time.Second))
+		}
+		if agent.Seat.TimeBankSeconds > 0 {
+			bank = time.Duration(agent.Seat.TimeBankSeconds * float64(time.Second))
+		}
+		g.actionTimeouts[name] = timeout
+		g.timeBanks[name] = bank
 	}
 }
 
+// SetActionDelay sets the pause between actions (0 for none)
+func (g *Game) SetActionDelay(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	g.actionDelay = d
+}
+
 func (g *Game) Start() *models.GameResult {
 	for !g.State.GameEnded {
 		select {
@@ -81,7 +190,9 @@ func (g *Game) Start() *models.GameResult {
 			return nil
 		default:
 			g.advanceGame()
-			time.Sleep(2 * time.Second)
+			if g.actionDelay > 0 {
+				time.Sleep(g.actionDelay)
+			}
 		}
 	}
 	log.Println("🏆 Tournament has ended! Game loop stopped.")
@@ -89,13 +200,27 @@ func (g *Game) Start() *models.GameResult {
 }
 
 func (g *Game) Stop() {
-	close(g.stopChan)
+	g.stopOnce.Do(func() { close(g.stopChan) })
 }
 
 func (g *Game) GetResult() *models.GameResult {
 	return g.result
 }
 
+// StyleStats returns every player's playing-style statistics over the hands
+// finished so far
+func (g *Game) StyleStats() map[string]models.StyleStats {
+	g.handsMu.Lock()
+	counts := models.CountStyle(g.hands)
+	g.handsMu.Unlock()
+
+	stats := make(map[string]models.StyleStats, len(counts))
+	for name, c := range counts {
+		stats[name] = c.Stats()
+	}
+	return stats
+}
+
 func (g *Game) GetStartTime() time.Time {
 	return g.startTime
 }
@@ -123,13 +248,8 @@ func (g *Game) checkChipConservation() bool {
 	}
 	totalChips := totalPlayerChips + g.State.Pot
 
-	// Initialize the expected chips on first call
-	if initialTotalChips == nil {
-		initialTotalChips = &totalChips
-	}
-
-	if totalChips != *initialTotalChips {
-		log.Printf("🚨 CHIP LEAK DETECTED! Expected: %d, Actual: %d", *initialTotalChips, totalChips)
+	if totalChips != g.expectedChips {
+		log.Printf("🚨 CHIP LEAK DETECTED! Expected: %d, Actual: %d", g.expectedChips, totalChips)
 		log.Printf("Player chips: %d, Pot: %d", totalPlayerChips, g.State.Pot)
 
 		balances := make([]string, len(g.State.Players))
@@ -138,7 +258,7 @@ func (g *Game) checkChipConservation() bool {
 		}
 		log.Printf("Player balances: %s", strings.Join(balances, ", "))
 	}
-	return totalChips == *initialTotalChips
+	return totalChips == g.expectedChips
 }
 
 func (g *Game) checkForEliminations() []string {
@@ -166,28 +286,23 @@ func (g *Game) getActivePlayers() []models.Player {
 }
 
 func (g *Game) checkForTournamentEnd() bool {
+	if g.cash != nil {
+		return g.checkForCashGameEnd()
+	}
+
 	activePlayers := g.getActivePlayers()
 
 	if len(activePlayers) == 1 {
 		g.State.GameEnded = true
 		winner := activePlayers[0]
-		
+
 		// Create game result
 		duration := time.Since(g.startTime)
-		g.result = &models.GameResult{
-			GameID:       g.ID,
-			Winner:       winner,
-			TotalHands:   g.State.HandNumber,
-			AllPlayers:   g.State.Players,
-			Eliminated:   g.State.EliminatedPlayers,
-			FinalChips:   winner.Chips,
-			GameDuration: duration.String(),
-			StartTime:    g.startTime,
-			EndTime:      time.Now(),
-		}
-		
+		g.result = g.newResult(winner)
+		g.result.PlayerRankings = models.RankStandings(g.State.Players, g.bustPlaces())
+
 		g.addToLog(fmt.Sprintf("🏆 TOURNAMENT WINNER: %s wins with $%d! 🏆", winner.Name, winner.Chips))
-		log.Printf("🏆 Tournament ended! Winner: %s with $%d in %d hands (Duration: %v)", 
+		log.Printf("🏆 Tournament ended! Winner: %s with $%d in %d hands (Duration: %v)",
 			winner.Name, winner.Chips, g.State.HandNumber, duration)
 		return true
 	}
@@ -195,6 +310,82 @@ func (g *Game) checkForTournamentEnd() bool {
 	return false
 }
 
+// newResult builds the game's result as it stands
+func (g *Game) newResult(winner models.Player) *models.GameResult {
+	for name, counts := range models.CountStyle(g.hands) {
+		if stats, ok := g.stats[name]; ok {
+			stats.Style = *counts
+		}
+	}
+	for _, player := range g.State.Players {
+		if stats, ok := g.stats[player.Name]; ok {
+			stats.NetChips = player.Chips - stats.BuyIn
+		}
+	}
+	buyIn := startingChips
+	if g.cash != nil {
+		buyIn = g.cash.BuyIn
+	}
+	return &models.GameResult{
+		GameID:        g.ID,
+		Winner:        winner,
+		TotalHands:    g.State.HandNumber,
+		AllPlayers:    g.State.Players,
+		Eliminated:    g.State.EliminatedPlayers,
+		FinalChips:    winner.Chips,
+		GameDuration:  time.Since(g.startTime).String(),
+		StartTime:     g.startTime,
+		EndTime:       time.Now(),
+		PlayerStats:   g.stats,
+		StartingChips: buyIn,
+		Hands:         g.hands,
+		Seats:         g.seats,
+		FallbackUsed:  g.fallbackUsed,
+		BigBlind:      g.State.BigBlind,
+		CashGame:      g.cash != nil,
+	}
+}
+
+// InterruptedResult scores a game that was stopped before it finished.
+// The hand in progress is called off, giving players back what they put in.
+// Cash games rank by net chips as usual; otherwise players are ranked and
+// paid as models.RankUnfinished describes. It returns nil for a game that
+// never dealt a hand, and must not be called while the game is running.
+func (g *Game) InterruptedResult(payouts []float64) *models.GameResult {
+	if g.State.GameEnded || (len(g.hands) == 0 && g.State.Pot == 0) {
+		return nil
+	}
+
+	if g.State.Pot > 0 {
+		for _, action := range g.State.HandActions {
+			for i := range g.State.Players {
+				if g.State.Players[i].Name == action.Player {
+					g.State.Players[i].Chips += action.Amount
+				}
+			}
+		}
+		g.State.Pot = 0
+		g.State.PlayerBets = make(map[string]int)
+		g.addToLog("Game stopped: hand called off")
+	}
+
+	result := g.newResult(models.Player{})
+	result.Interrupted = true
+	if g.cash != nil {
+		result.PlayerRankings = cashRankings(g.State.Players, g.stats)
+	} else {
+		result.PlayerRankings = models.RankUnfinished(g.State.Players, g.bustPlaces(), payouts)
+	}
+	result.Winner = result.PlayerRankings[0].Player
+	result.FinalChips = result.Winner.Chips
+	return result
+}
+
+// bustPlaces returns the finishing places of the players who have busted
+func (g *Game) bustPlaces() map[string]int {
+	return models.BustPlaces(len(g.State.Players), g.hands)
+}
+
 func (g *Game) advanceGame() {
 	// Check if tournament has ended
 	if g.State.GameEnded {
@@ -213,12 +404,19 @@ func (g *Game) advanceGame() {
 			return
 		}
 
+		if g.checkpoint != nil {
+			if snapshot := g.snapshot(); snapshot != nil {
+				g.checkpoint(snapshot)
+			}
+		}
+
 		// Initialize new hand
-		g.State.Deck = poker.InitializeDeck()
+		g.State.Deck = poker.InitializeDeckWithRand(g.rng)
 		g.State.CurrentBet = 0
 		g.State.PlayerBets = make(map[string]int)
 		g.State.FoldedPlayers = []string{}
 		g.State.BettingComplete = false
+		g.State.HandActions = []models.ActionRecord{}
 
 		// Deal cards only to active players
 		for i := range g.State.Players {
@@ -245,12 +443,10 @@ func (g *Game) advanceGame() {
 	if !contains(g.State.FoldedPlayers, currentPlayer.Name) &&
 		!contains(g.State.EliminatedPlayers, currentPlayer.Name) {
 
-		decision, err := ai.GetAIDecision(currentPlayer, g.State)
-		if err != nil {
-			log.Printf("Error getting AI decision: %v", err)
-			decision = "fold"
-		}
-		g.processDecision(decision, g.State.CurrentPlayer)
+		decision := g.decide(currentPlayer)
+		g.recordDecision(currentPlayer.Name, decision)
+		g.processDecision(decision.String(), g.State.CurrentPlayer)
+		g.annotateLastAction(currentPlayer, decision)
 		g.checkChipConservation()
 	}
 
@@ -277,6 +473,89 @@ func (g *Game) advanceGame() {
 	}
 }
 
+// decide asks the player's agent for a decision within their time limit,
+// drawing on their time bank once the limit is used up. A player who runs
+// out of time checks if they can, otherwise folds.
+func (g *Game) decide(player models.Player) ai.Decision {
+	limit := g.actionTimeouts[player.Name]
+	bank := g.timeBanks[player.Name]
+
+	// The clock stops while requests wait for their provider's rate
+	// limiter, so only the decision's own time counts against the limit
+	ctx := context.Background()
+	var clock *ai.DecisionClock
+	if limit > 0 {
+		var cancel context.CancelFunc
+		ctx, clock, cancel = ai.WithDecisionClock(ctx, limit+bank)
+		defer cancel()
+	}
+
+	start := time.Now()
+	decision, err := g.agents[player.Name].Decide(ctx, player, g.State)
+	elapsed := time.Since(start)
+	charged := clock.Used()
+
+	stats := g.stats[player.Name]
+	stats.TotalDecisionMs += elapsed.Milliseconds()
+	if elapsed.Milliseconds() > stats.MaxDecisionMs {
+		stats.MaxDecisionMs = elapsed.Milliseconds()
+	}
+	if limit > 0 && charged > limit && bank > 0 {
+		used := charged - limit
+		if used > bank {
+			used = bank
+		}
+		g.timeBanks[player.Name] = bank - used
+		stats.TimeBankUsedMs += used.Milliseconds()
+		g.addToLog(fmt.Sprintf("⏱️ %s used %.1fs of their time bank (%.1fs left)",
+			player.Name, used.Seconds(), (bank - used).Seconds()))
+	}
+
+	if clock.Expired() || errors.Is(err, context.DeadlineExceeded) {
+		stats.Timeouts++
+		decision.Action = "fold"
+		decision.RaiseTo = 0
+		if g.State.PlayerBets[player.Name] >= g.State.CurrentBet {
+			decision.Action = "check"
+		}
+		g.addToLog(fmt.Sprintf("⏱️ %s ran out of time after %.1fs, auto-%s", player.Name, charged.Seconds(), decision.Action))
+		return decision
+	}
+	if err != nil {
+		log.Printf("Error getting AI decision: %v", err)
+		decision.Action = "fold"
+	}
+	return decision
+}
+
+// recordDecision updates a player's decision statistics and logs any
+// invalid answers the model gave first
+func (g *Game) recordDecision(playerName string, decision ai.Decision) {
+	stats := g.stats[playerName]
+	stats.Decisions++
+	stats.InvalidActions += len(decision.InvalidAttempts)
+
+	usage := decision.Usage
+	stats.Requests += usage.Requests
+	stats.PromptTokens += usage.PromptTokens
+	stats.CompletionTokens += usage.CompletionTokens
+	stats.Cost += decision.Cost
+	stats.TotalLatencyMs += usage.Latency.Milliseconds()
+
+	for _, attempt := range decision.InvalidAttempts {
+		g.addToLog(fmt.Sprintf("⚠️ %s tried an invalid action: %s", playerName, attempt.Error))
+	}
+	if decision.Defaulted {
+		stats.DefaultedActions++
+		g.addToLog(fmt.Sprintf("⚠️ %s gave no legal action, defaulting to %s", playerName, decision.Action))
+	}
+	if decision.Fallback != "" {
+		stats.FallbackDecisions++
+		g.fallbackUsed = true
+		g.addToLog(fmt.Sprintf("⚠️ %s's model is unavailable (%s), %s decides instead", playerName, decision.FallbackReason, decision.Fallback))
+	}
+}
+
 // Helper functions
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
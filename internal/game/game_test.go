@@ -0,0 +1,260 @@
+package game
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/internal/ai"
+	"github.com/MikeLuu99/poker-arena/internal/ai/aitest"
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// playSeeded plays one game with the default lineup, dealt from seed,
+// against whatever the OpenRouter URL currently points at
+func playSeeded(t *testing.T, seed int64) *models.GameResult {
+	t.Helper()
+	g := NewGameWithSeats(1, nil)
+	g.SetSeed(seed)
+	g.SetActionDelay(0)
+	result := g.Start()
+	if result == nil {
+		t.Fatal("game returned no result")
+	}
+	return result
+}
+
+// playAgainst plays a seeded game against a fresh fake server
+func playAgainst(t *testing.T, seed int64, policy aitest.Policy) *models.GameResult {
+	t.Helper()
+	srv := aitest.NewServer(policy)
+	defer srv.Close()
+	defer srv.UseAsOpenRouter()()
+	return playSeeded(t, seed)
+}
+
+// handLog reduces a game to what was dealt and done, without timings
+func handLog(result *models.GameResult) [][]string {
+	var hands [][]string
+	for _, hand := range result.Hands {
+		var log []string
+		log = append(log, hand.Board...)
+		for _, action := range hand.Actions {
+			log = append(log, fmt.Sprintf("%s %s %d", action.Player, action.Action, action.Amount))
+		}
+		hands = append(hands, log)
+	}
+	return hands
+}
+
+func rankingNames(result *models.GameResult) []string {
+	var names []string
+	for _, ranking := range result.PlayerRankings {
+		names = append(names, ranking.Position+" "+ranking.Player.Name)
+	}
+	return names
+}
+
+func checkFinished(t *testing.T, result *models.GameResult) {
+	t.Helper()
+	total := len(DefaultSeats()) * startingChips
+	if result.FinalChips != total {
+		t.Errorf("winner has %d chips, want all %d", result.FinalChips, total)
+	}
+	if len(result.PlayerRankings) != len(DefaultSeats()) {
+		t.Fatalf("%d rankings, want %d", len(result.PlayerRankings), len(DefaultSeats()))
+	}
+	if first := result.PlayerRankings[0]; first.Rank != 1 || first.Player.Name != result.Winner.Name {
+		t.Errorf("first ranking is %+v, want the winner %s", first, result.Winner.Name)
+	}
+	for _, ranking := range result.PlayerRankings[1:] {
+		if ranking.Rank < 2 || ranking.Player.Chips != 0 {
+			t.Errorf("loser ranking %+v should be placed after the winner with no chips", ranking)
+		}
+	}
+	if result.TotalHands != len(result.Hands) || result.TotalHands == 0 {
+		t.Errorf("TotalHands %d, recorded hands %d", result.TotalHands, len(result.Hands))
+	}
+}
+
+func TestSeededGameIsReproducible(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "test")
+
+	first := playAgainst(t, 42, aitest.RandomLegal(7))
+	second := playAgainst(t, 42, aitest.RandomLegal(7))
+	checkFinished(t, first)
+
+	if !reflect.DeepEqual(handLog(first), handLog(second)) {
+		t.Error("same seed and policy played different hands")
+	}
+	if !reflect.DeepEqual(rankingNames(first), rankingNames(second)) {
+		t.Errorf("rankings differ: %v vs %v", rankingNames(first), rankingNames(second))
+	}
+
+	other := playAgainst(t, 43, aitest.RandomLegal(7))
+	if reflect.DeepEqual(handLog(first), handLog(other)) {
+		t.Error("different seeds dealt identical games")
+	}
+}
+
+func TestCallingStationsReachShowdown(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "test")
+
+	result := playAgainst(t, 5, aitest.Fixed("call", 0))
+	checkFinished(t, result)
+	for _, hand := range result.Hands {
+		if len(hand.Board) != 5 {
+			t.Errorf("hand %d ended with %d board cards; callers should see every street", hand.HandNumber, len(hand.Board))
+		}
+		for _, action := range hand.Actions {
+			if action.Action == "fold" || action.Action == "raise" {
+				t.Errorf("hand %d: %s %s, but every seat only calls", hand.HandNumber, action.Player, action.Action)
+			}
+		}
+	}
+}
+
+func TestReplayCassetteReproducesGame(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "test")
+	path := filepath.Join(t.TempDir(), "game.ndjson")
+
+	srv := aitest.NewServer(aitest.RandomLegal(3))
+	defer srv.Close()
+	defer srv.UseAsOpenRouter()()
+
+	cassette, err := ai.RecordCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := playSeeded(t, 99)
+	if err := cassette.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Any request reaching the server now is a cassette miss
+	requests := len(srv.Requests())
+	cassette, err = ai.ReplayCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cassette.Close()
+	replayed := playSeeded(t, 99)
+
+	if n := len(srv.Requests()) - requests; n != 0 {
+		t.Errorf("replay sent %d requests to the server", n)
+	}
+	checkFinished(t, replayed)
+	if !reflect.DeepEqual(handLog(recorded), handLog(replayed)) {
+		t.Error("replayed game differs from the recording")
+	}
+	if !reflect.DeepEqual(rankingNames(recorded), rankingNames(replayed)) {
+		t.Errorf("rankings differ: %v vs %v", rankingNames(recorded), rankingNames(replayed))
+	}
+}
+
+func TestRateLimitWaitsDontCountAgainstActionTimeout(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "test")
+	srv := aitest.NewServer(aitest.RateLimited(1, "1", aitest.Fixed("call", 0)))
+	defer srv.Close()
+	defer srv.UseAsOpenRouter()()
+
+	g := NewGameWithSeats(1, nil)
+	g.SetSeed(8)
+	g.SetActionDelay(0)
+	g.SetTimeControl(500*time.Millisecond, 0)
+	result := g.Start()
+	if result == nil {
+		t.Fatal("game returned no result")
+	}
+	for name, stats := range result.PlayerStats {
+		if stats.Timeouts > 0 {
+			t.Errorf("%s timed out %d times waiting out a one-second backoff", name, stats.Timeouts)
+		}
+	}
+}
+
+// botSeats is the default lineup with every model failing over to the
+// random bot
+func botSeats() []models.SeatConfig {
+	seats := DefaultSeats()
+	for i := range seats {
+		seats[i].Fallback = &models.FallbackConfig{Bot: "random"}
+	}
+	return seats
+}
+
+func TestRandomBotIsSeededAndResumes(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "test")
+	srv := aitest.NewServer(aitest.Status(400))
+	defer srv.Close()
+	defer srv.UseAsOpenRouter()()
+
+	play := func(restore *Snapshot) (*models.GameResult, map[int]*Snapshot) {
+		snapshots := make(map[int]*Snapshot)
+		g := NewGameWithSeats(1, botSeats())
+		g.SetSeed(15)
+		g.SetActionDelay(0)
+		g.SetCheckpoint(func(s *Snapshot) { snapshots[s.State.HandNumber] = s })
+		if restore != nil {
+			g.Restore(restore)
+		}
+		result := g.Start()
+		if result == nil {
+			t.Fatal("game returned no result")
+		}
+		return result, snapshots
+	}
+
+	first, snapshots := play(nil)
+	second, _ := play(nil)
+	if !reflect.DeepEqual(handLog(first), handLog(second)) {
+		t.Fatal("same seed played different hands with random bots")
+	}
+	if len(first.Hands) < 10 {
+		t.Fatalf("game lasted %d hands, too short to resume", len(first.Hands))
+	}
+
+	// Resume halfway, once the bots have drawn plenty
+	snapshot := snapshots[len(first.Hands)/2]
+	if len(snapshot.BotDraws) == 0 {
+		t.Fatal("snapshot recorded no bot draws")
+	}
+	resumed, _ := play(snapshot)
+	if !reflect.DeepEqual(handLog(first), handLog(resumed)) {
+		t.Error("resumed game played differently from the uninterrupted one")
+	}
+}
+
+func TestShowdownsAreRecorded(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "test")
+
+	for seed := int64(1); seed <= 5; seed++ {
+		result := playAgainst(t, seed, aitest.RandomLegal(seed))
+		for _, hand := range result.Hands {
+			// Everyone dealt in who didn't fold reaches the showdown
+			live := make(map[string]bool)
+			for _, action := range hand.Actions {
+				live[action.Player] = action.Action != "fold"
+			}
+			var want []string
+			for name, in := range live {
+				if in {
+					want = append(want, name)
+				}
+			}
+			if len(want) < 2 {
+				if len(hand.Showdown) != 0 {
+					t.Errorf("seed %d hand %d: showdown %v with one player left", seed, hand.HandNumber, hand.Showdown)
+				}
+				continue
+			}
+			for _, name := range want {
+				if len(hand.Showdown[name]) != 2 {
+					t.Errorf("seed %d hand %d: %s reached showdown but showed %v", seed, hand.HandNumber, name, hand.Showdown[name])
+				}
+			}
+		}
+	}
+}
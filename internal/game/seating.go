@@ -0,0 +1,145 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/internal/ai"
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// Seat changes for multi-table play. A game only changes seats between
+// hands, on its own goroutine, from the function set with SetBetweenHands;
+// a coordinator moving a player removes them from one game there and adds
+// them to another game from that game's own callback.
+
+// Transfer is a player leaving one table for another, with everything that
+// follows them: chips, agent and its memory, statistics and time bank
+type Transfer struct {
+	Player models.Player
+
+	seat     models.SeatConfig
+	agent    *ai.Agent
+	stats    *models.PlayerGameStats
+	timeout  time.Duration
+	timeBank time.Duration
+}
+
+// SetBetweenHands sets a function to run after every hand, once the hand is
+// recorded and before the game checks whether it is over. The function may
+// add and remove players or close the table.
+func (g *Game) SetBetweenHands(fn func(g *Game)) {
+	g.betweenHands = fn
+}
+
+// LastHand returns the hand that just finished
+func (g *Game) LastHand() models.HandRecord {
+	g.handsMu.Lock()
+	defer g.handsMu.Unlock()
+	if len(g.hands) == 0 {
+		return models.HandRecord{}
+	}
+	return g.hands[len(g.hands)-1]
+}
+
+// ActivePlayers returns the players still in the game
+func (g *Game) ActivePlayers() []models.Player {
+	return g.getActivePlayers()
+}
+
+// NextBigBlind returns the player due to post the big blind next hand, the
+// usual choice when a player has to move to another table
+func (g *Game) NextBigBlind() string {
+	active := g.getActivePlayers()
+	if len(active) == 0 {
+		return ""
+	}
+
+	dealer := 0
+	for i, player := range active {
+		if player.Name == g.State.Players[g.State.DealerPosition].Name {
+			dealer = i
+			break
+		}
+	}
+	// The button moves one seat before the next hand's blinds
+	return active[(dealer+3)%len(active)].Name
+}
+
+// RemovePlayer takes an active player off the table between hands
+func (g *Game) RemovePlayer(name string) (*Transfer, bool) {
+	index := -1
+	for i, player := range g.State.Players {
+		if player.Name == name && !contains(g.State.EliminatedPlayers, name) {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return nil, false
+	}
+
+	player := g.State.Players[index]
+	player.Cards = []string{}
+	transfer := &Transfer{
+		Player:   player,
+		agent:    g.agents[name],
+		stats:    g.stats[name],
+		timeout:  g.actionTimeouts[name],
+		timeBank: g.timeBanks[name],
+	}
+	for i, seat := range g.seats {
+		if seat.Name == name {
+			transfer.seat = seat
+			g.seats = append(g.seats[:i:i], g.seats[i+1:]...)
+			break
+		}
+	}
+
+	g.State.Players = append(g.State.Players[:index:index], g.State.Players[index+1:]...)
+	delete(g.agents, name)
+	delete(g.stats, name)
+	delete(g.actionTimeouts, name)
+	delete(g.timeBanks, name)
+	g.expectedChips -= player.Chips
+
+	// Keep the button where it was. If the player leaving had it, step it
+	// back a seat so it still moves on to the next player after this hand.
+	if index <= g.State.DealerPosition {
+		g.State.DealerPosition--
+	}
+	if len(g.State.Players) > 0 {
+		g.State.DealerPosition = (g.State.DealerPosition + len(g.State.Players)) % len(g.State.Players)
+	} else {
+		g.State.DealerPosition = 0
+	}
+	g.State.CurrentPlayer = g.State.DealerPosition
+
+	g.addToLog(fmt.Sprintf("🪑 %s leaves the table with $%d", name, player.Chips))
+	return transfer, true
+}
+
+// AddPlayer seats a player from another table between hands
+func (g *Game) AddPlayer(transfer *Transfer) {
+	name := transfer.Player.Name
+	g.State.Players = append(g.State.Players, transfer.Player)
+	g.seats = append(g.seats, transfer.seat)
+	g.agents[name] = transfer.agent
+	g.stats[name] = transfer.stats
+	g.actionTimeouts[name] = transfer.timeout
+	g.timeBanks[name] = transfer.timeBank
+	g.expectedChips += transfer.Player.Chips
+
+	g.addToLog(fmt.Sprintf("🪑 %s joins the table with $%d", name, transfer.Player.Chips))
+}
+
+// Close ends a table that has been broken up. Its result keeps the hands
+// played there and the statistics of players who busted at it.
+func (g *Game) Close() {
+	if g.State.GameEnded {
+		return
+	}
+	g.State.GameEnded = true
+	g.result = g.newResult(models.Player{})
+	g.addToLog("Table closed")
+}
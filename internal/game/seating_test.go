@@ -0,0 +1,80 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+func seatNames(g *Game) []string {
+	var names []string
+	for _, player := range g.State.Players {
+		names = append(names, player.Name)
+	}
+	return names
+}
+
+func TestMovePlayerBetweenTables(t *testing.T) {
+	from := NewGameWithSeats(1, []models.SeatConfig{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+	to := NewGameWithSeats(2, []models.SeatConfig{{Name: "d"}, {Name: "e"}})
+	from.State.DealerPosition = 2
+	from.State.Players[1].Chips = 35
+	from.expectedChips += 15
+	from.stats["b"].Decisions = 4
+
+	transfer, ok := from.RemovePlayer("b")
+	if !ok {
+		t.Fatal("couldn't remove b")
+	}
+	if _, ok := from.RemovePlayer("b"); ok {
+		t.Error("removed b twice")
+	}
+	if got := seatNames(from); len(got) != 2 || got[0] != "a" || got[1] != "c" {
+		t.Errorf("table 1 seats %v, want [a c]", got)
+	}
+	if from.State.Players[from.State.DealerPosition].Name != "c" {
+		t.Errorf("button moved to %s, want it to stay on c", from.State.Players[from.State.DealerPosition].Name)
+	}
+	if from.expectedChips != 40 {
+		t.Errorf("table 1 expects %d chips, want 40", from.expectedChips)
+	}
+
+	to.AddPlayer(transfer)
+	if got := seatNames(to); len(got) != 3 || got[2] != "b" {
+		t.Errorf("table 2 seats %v, want b added last", got)
+	}
+	if to.State.Players[2].Chips != 35 || to.expectedChips != 75 {
+		t.Errorf("b has %d chips at a table expecting %d, want 35 and 75", to.State.Players[2].Chips, to.expectedChips)
+	}
+	if to.stats["b"].Decisions != 4 || to.agents["b"] == nil {
+		t.Error("b's statistics and agent didn't follow them")
+	}
+}
+
+func TestRemovingTheButtonStepsItBack(t *testing.T) {
+	g := NewGameWithSeats(1, []models.SeatConfig{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}})
+	g.State.DealerPosition = 1
+	if _, ok := g.RemovePlayer("b"); !ok {
+		t.Fatal("couldn't remove b")
+	}
+	// The button passes to c after the next hand, as it would have
+	if got := g.State.Players[g.State.DealerPosition].Name; got != "a" {
+		t.Errorf("button on %s, want a", got)
+	}
+	if got := g.NextBigBlind(); got != "a" {
+		t.Errorf("next big blind %s, want a", got)
+	}
+}
+
+func TestClosedTableKeepsItsHands(t *testing.T) {
+	g := NewGameWithSeats(1, []models.SeatConfig{{Name: "a"}, {Name: "b"}})
+	g.hands = []models.HandRecord{{HandNumber: 1}}
+	g.Close()
+	if !g.State.GameEnded {
+		t.Fatal("closed table still running")
+	}
+	result := g.GetResult()
+	if result == nil || len(result.Hands) != 1 || result.Winner.Name != "" {
+		t.Errorf("closed table result %+v, want its hand and no winner", result)
+	}
+}
@@ -6,6 +6,25 @@ import (
 )
 
 func InitializeDeck() []string {
+	return Shuffle(newDeck())
+}
+
+// InitializeDeckWithRand returns a deck shuffled by rng, so a seeded rng
+// deals the same cards every time
+func InitializeDeckWithRand(rng *rand.Rand) []string {
+	if rng == nil {
+		return InitializeDeck()
+	}
+
+	deck := newDeck()
+	for i := len(deck) - 1; i > 0; i-- {
+		j := rng.Intn(i + 1)
+		deck[i], deck[j] = deck[j], deck[i]
+	}
+	return deck
+}
+
+func newDeck() []string {
 	suits := []string{"♠", "♣", "♥", "♦"}
 	values := []string{"2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K", "A"}
 	var deck []string
@@ -16,7 +35,7 @@ func InitializeDeck() []string {
 		}
 	}
 
-	return Shuffle(deck)
+	return deck
 }
 
 func Shuffle(array []string) []string {
@@ -26,4 +45,4 @@ func Shuffle(array []string) []string {
 		array[i], array[j] = array[j], array[i]
 	}
 	return array
-}
\ No newline at end of file
+}
@@ -0,0 +1,79 @@
+// Package rating keeps skill ratings for players across games and runs.
+//
+// Ratings use the Weng-Lin Bradley-Terry model (the one behind OpenSkill):
+// each player's skill is a normal distribution with mean Mu and standard
+// deviation Sigma. After every game each player is compared with every
+// other player by finishing position, Mu moves toward the observed result,
+// and Sigma shrinks as evidence accumulates.
+package rating
+
+import (
+	"math"
+	"time"
+)
+
+// Model constants, on the usual TrueSkill scale
+const (
+	DefaultMu    = 25.0
+	DefaultSigma = DefaultMu / 3
+	beta         = DefaultSigma / 2 // performance variation within a game
+	kappa        = 0.0001           // floor on the sigma shrink factor
+	z95          = 1.96
+)
+
+// Rating is a player's estimated skill
+type Rating struct {
+	Mu      float64   `json:"mu"`
+	Sigma   float64   `json:"sigma"`
+	Games   int       `json:"games"`
+	Updated time.Time `json:"updated"`
+}
+
+// NewRating returns the prior for a player with no games
+func NewRating() Rating {
+	return Rating{Mu: DefaultMu, Sigma: DefaultSigma}
+}
+
+// Conservative is the skill the player has with high confidence
+// (Mu - 3*Sigma), the usual value to rank by
+func (r Rating) Conservative() float64 {
+	return r.Mu - 3*r.Sigma
+}
+
+// Interval returns the 95% confidence interval for the player's skill
+func (r Rating) Interval() (low, high float64) {
+	return r.Mu - z95*r.Sigma, r.Mu + z95*r.Sigma
+}
+
+// Update returns the ratings after a game. ranks[i] is player i's finishing
+// position (1 for the winner); equal ranks are ties.
+func Update(ratings []Rating, ranks []int) []Rating {
+	updated := make([]Rating, len(ratings))
+	for i, r := range ratings {
+		var omega, delta float64
+		for q, other := range ratings {
+			if q == i {
+				continue
+			}
+			c := math.Sqrt(r.Sigma*r.Sigma + other.Sigma*other.Sigma + 2*beta*beta)
+			p := 1 / (1 + math.Exp((other.Mu-r.Mu)/c)) // chance i beats q
+
+			score := 0.5
+			if ranks[i] < ranks[q] {
+				score = 1
+			} else if ranks[i] > ranks[q] {
+				score = 0
+			}
+
+			gamma := r.Sigma / c
+			omega += r.Sigma * r.Sigma / c * (score - p)
+			delta += gamma * r.Sigma * r.Sigma / (c * c) * p * (1 - p)
+		}
+
+		updated[i] = r
+		updated[i].Mu = r.Mu + omega
+		updated[i].Sigma = r.Sigma * math.Sqrt(math.Max(1-delta, kappa))
+		updated[i].Games = r.Games + 1
+	}
+	return updated
+}
@@ -0,0 +1,41 @@
+package rating
+
+import "testing"
+
+func TestUpdate(t *testing.T) {
+	before := []Rating{NewRating(), NewRating(), NewRating()}
+	after := Update(before, []int{2, 1, 3})
+
+	if !(after[1].Mu > after[0].Mu && after[0].Mu > after[2].Mu) {
+		t.Errorf("means %.2f, %.2f, %.2f don't follow the finishing order", after[1].Mu, after[0].Mu, after[2].Mu)
+	}
+	if after[1].Mu <= DefaultMu || after[2].Mu >= DefaultMu {
+		t.Errorf("winner's mean %.2f, loser's %.2f, want them either side of %.2f", after[1].Mu, after[2].Mu, DefaultMu)
+	}
+	for i, r := range after {
+		if r.Sigma >= before[i].Sigma {
+			t.Errorf("player %d: sigma %.3f didn't shrink from %.3f", i, r.Sigma, before[i].Sigma)
+		}
+		if r.Games != 1 {
+			t.Errorf("player %d: %d games, want 1", i, r.Games)
+		}
+	}
+}
+
+func TestUpdateTie(t *testing.T) {
+	after := Update([]Rating{NewRating(), NewRating()}, []int{1, 1})
+	if after[0].Mu != DefaultMu || after[1].Mu != DefaultMu {
+		t.Errorf("tie between equals moved the means to %.2f and %.2f", after[0].Mu, after[1].Mu)
+	}
+}
+
+func TestUpsetMovesMore(t *testing.T) {
+	strong := Rating{Mu: 35, Sigma: 3}
+	weak := Rating{Mu: 15, Sigma: 3}
+
+	expected := Update([]Rating{strong, weak}, []int{1, 2})
+	upset := Update([]Rating{strong, weak}, []int{2, 1})
+	if gain, loss := expected[0].Mu-strong.Mu, strong.Mu-upset[0].Mu; gain >= loss {
+		t.Errorf("favourite gains %.3f for winning but loses only %.3f for losing", gain, loss)
+	}
+}
@@ -0,0 +1,156 @@
+package rating
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// Store holds every player's rating and persists them to a JSON file, so
+// ratings accumulate across runs
+type Store struct {
+	mu      sync.Mutex
+	path    string
+	Players map[string]Rating `json:"players"`
+	Games   int               `json:"games"` // games rated so far, across all runs
+	Updated time.Time         `json:"updated"`
+}
+
+// Entry is one line of the leaderboard
+type Entry struct {
+	Rank   int
+	Name   string
+	Rating Rating
+}
+
+// Load reads the ratings file at path, or starts empty if it doesn't exist
+func Load(path string) (*Store, error) {
+	store := &Store{path: path, Players: make(map[string]Rating)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ratings: %w", err)
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse ratings %s: %w", path, err)
+	}
+	if store.Players == nil {
+		store.Players = make(map[string]Rating)
+	}
+	return store, nil
+}
+
+// Save writes the ratings back to their file
+func (s *Store) Save() error {
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated
+	// ratings file behind
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save ratings: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save ratings: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save ratings: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save ratings: %w", err)
+	}
+	return nil
+}
+
+// Get returns a player's rating, or the prior if they have none
+func (s *Store) Get(name string) Rating {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r, ok := s.Players[name]; ok {
+		return r
+	}
+	return NewRating()
+}
+
+// AddGame updates the ratings of everyone in a finished game from their
+// finishing positions
+func (s *Store) AddGame(result *models.GameResult) {
+	if len(result.PlayerRankings) < 2 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ratings := make([]Rating, len(result.PlayerRankings))
+	ranks := make([]int, len(result.PlayerRankings))
+	for i, ranking := range result.PlayerRankings {
+		ratings[i] = NewRating()
+		if r, ok := s.Players[ranking.Player.Name]; ok {
+			ratings[i] = r
+		}
+		ranks[i] = ranking.Rank
+	}
+
+	now := time.Now()
+	for i, r := range Update(ratings, ranks) {
+		r.Updated = now
+		s.Players[result.PlayerRankings[i].Player.Name] = r
+	}
+	s.Games++
+	s.Updated = now
+}
+
+// Snapshot returns a copy of every player's current rating
+func (s *Store) Snapshot() map[string]Rating {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := make(map[string]Rating, len(s.Players))
+	for name, r := range s.Players {
+		snapshot[name] = r
+	}
+	return snapshot
+}
+
+// Leaderboard returns every player by conservative rating, best first
+func (s *Store) Leaderboard() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]Entry, 0, len(s.Players))
+	for name, r := range s.Players {
+		entries = append(entries, Entry{Name: name, Rating: r})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i].Rating.Conservative(), entries[j].Rating.Conservative()
+		if a != b {
+			return a > b
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries
+}
@@ -0,0 +1,48 @@
+package rating
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratings.json")
+	store, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.Players) != 0 || store.Games != 0 {
+		t.Fatalf("missing file loaded %d players and %d games, want none", len(store.Players), store.Games)
+	}
+
+	store.AddGame(&models.GameResult{PlayerRankings: []models.PlayerRanking{
+		{Player: models.Player{Name: "a"}, Rank: 1},
+		{Player: models.Player{Name: "b"}, Rank: 2},
+		{Player: models.Player{Name: "c"}, Rank: 3},
+	}})
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Games != 1 || len(loaded.Players) != 3 {
+		t.Fatalf("loaded %d players and %d games, want 3 and 1", len(loaded.Players), loaded.Games)
+	}
+	for name, want := range store.Players {
+		got := loaded.Get(name)
+		if got.Mu != want.Mu || got.Sigma != want.Sigma || got.Games != want.Games || !got.Updated.Equal(want.Updated) {
+			t.Errorf("%s loaded as %+v, want %+v", name, got, want)
+		}
+	}
+	if board := loaded.Leaderboard(); board[0].Name != "a" || board[2].Name != "c" {
+		t.Errorf("leaderboard %v, want a first and c last", board)
+	}
+	if got := loaded.Get("d"); got != NewRating() {
+		t.Errorf("unrated player %+v, want the prior", got)
+	}
+}
@@ -0,0 +1,169 @@
+package results
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// The database has two buckets: runs, holding each run's metadata by run
+// ID, and games, holding a bucket per run ID with each finished game's
+// result, hands and actions by game ID.
+var (
+	runsBucket  = []byte("runs")
+	gamesBucket = []byte("games")
+)
+
+// lockTimeout is how long to wait for another process using the database.
+// The file is opened only for each read or write, so runs and queries can
+// share it.
+const lockTimeout = 10 * time.Second
+
+// DB is a results database file collecting runs over time
+type DB struct {
+	path string
+	mu   sync.Mutex // one open at a time within the process
+}
+
+// Open creates the database file if needed and checks that it can be used
+func Open(path string) (*DB, error) {
+	db := &DB{path: path}
+	err := db.update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(runsBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(gamesBucket)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open results database: %w", err)
+	}
+	return db, nil
+}
+
+// OpenExisting opens a database that must already exist, for queries
+func OpenExisting(path string) (*DB, error) {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no results database at %s", path)
+	}
+	return Open(path)
+}
+
+func (db *DB) update(fn func(tx *bolt.Tx) error) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	bdb, err := bolt.Open(db.path, 0o644, &bolt.Options{Timeout: lockTimeout})
+	if err != nil {
+		return err
+	}
+	defer bdb.Close()
+	return bdb.Update(fn)
+}
+
+func (db *DB) view(fn func(tx *bolt.Tx) error) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	bdb, err := bolt.Open(db.path, 0o644, &bolt.Options{Timeout: lockTimeout, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer bdb.Close()
+	return bdb.View(fn)
+}
+
+// putRun writes a run's metadata
+func putRun(tx *bolt.Tx, run *Run) error {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(runsBucket).Put([]byte(run.ID), data)
+}
+
+// getRun reads a run's metadata, or nil if there is no such run
+func getRun(tx *bolt.Tx, id string) (*Run, error) {
+	data := tx.Bucket(runsBucket).Get([]byte(id))
+	if data == nil {
+		return nil, nil
+	}
+	run := &Run{}
+	if err := json.Unmarshal(data, run); err != nil {
+		return nil, fmt.Errorf("failed to parse run %s: %w", id, err)
+	}
+	return run, nil
+}
+
+// gameKey orders a run's games by ID
+func gameKey(gameID int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(gameID))
+	return key
+}
+
+// Runs returns every run, oldest first
+func (db *DB) Runs() ([]*Run, error) {
+	var runs []*Run
+	err := db.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(runsBucket).ForEach(func(k, v []byte) error {
+			run := &Run{}
+			if err := json.Unmarshal(v, run); err != nil {
+				return fmt.Errorf("failed to parse run %s: %w", k, err)
+			}
+			runs = append(runs, run)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].StartTime.Before(runs[j].StartTime)
+	})
+	return runs, nil
+}
+
+// Run returns one run's metadata
+func (db *DB) Run(id string) (*Run, error) {
+	var run *Run
+	err := db.view(func(tx *bolt.Tx) error {
+		var err error
+		run, err = getRun(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, fmt.Errorf("no run %q in the results database", id)
+	}
+	return run, nil
+}
+
+// Games returns a run's finished games in game order
+func (db *DB) Games(runID string) ([]*models.GameResult, error) {
+	var games []*models.GameResult
+	err := db.view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(gamesBucket).Bucket([]byte(runID))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			result := &models.GameResult{}
+			if err := json.Unmarshal(v, result); err != nil {
+				return fmt.Errorf("failed to parse game %d of run %s: %w", binary.BigEndian.Uint64(k), runID, err)
+			}
+			games = append(games, result)
+			return nil
+		})
+	})
+	return games, err
+}
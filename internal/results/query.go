@@ -0,0 +1,259 @@
+package results
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// defaultDatabase is where query looks for results when --db isn't given
+const defaultDatabase = "poker_results.db"
+
+// Query runs the query subcommand: a report over the runs saved in a
+// results database
+func Query(args []string) {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	dbFile := fs.String("db", defaultDatabase, "Results database to read")
+	runIDs := fs.String("run", "", "Comma-separated run IDs to report on (default: all runs)")
+	since := fs.String("since", "", "Only runs started on or after this date (YYYY-MM-DD)")
+	gameID := fs.Int("game", 0, "Game to list actions for (actions report)")
+	player := fs.String("player", "", "Only this player's actions (actions report)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s query <report> [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Reports:\n")
+		fmt.Fprintf(os.Stderr, "  runs         Every run with its status, seed, version and models\n")
+		fmt.Fprintf(os.Stderr, "  leaderboard  Player statistics over the games of the selected runs\n")
+		fmt.Fprintf(os.Stderr, "  games        Each game of the selected runs\n")
+		fmt.Fprintf(os.Stderr, "  actions      Every action of one game (needs --run and --game)\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s query runs\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s query leaderboard --since 2026-10-01\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s query actions --run 20261018-153045-0a1b --game 3 --player gpt-5\n", os.Args[0])
+	}
+
+	// Options may come before or after the report name
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	report := fs.Arg(0)
+	fs.Parse(fs.Args()[1:])
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		os.Exit(2)
+	}
+
+	db, err := OpenExisting(*dbFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	runs, err := selectRuns(db, *runIDs, *since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch report {
+	case "runs":
+		err = queryRuns(runs)
+	case "leaderboard":
+		err = queryLeaderboard(db, runs)
+	case "games":
+		err = queryGames(db, runs)
+	case "actions":
+		if len(runs) != 1 || *runIDs == "" || *gameID == 0 {
+			err = fmt.Errorf("the actions report needs one --run and a --game")
+			break
+		}
+		err = queryActions(db, runs[0], *gameID, *player)
+	default:
+		err = fmt.Errorf("unknown report %q (use runs, leaderboard, games or actions)", report)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// selectRuns returns the runs named by ids, or every run, started on or
+// after since if given
+func selectRuns(db *DB, ids, since string) ([]*Run, error) {
+	var runs []*Run
+	if ids == "" {
+		var err error
+		if runs, err = db.Runs(); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, id := range strings.Split(ids, ",") {
+			run, err := db.Run(strings.TrimSpace(id))
+			if err != nil {
+				return nil, err
+			}
+			runs = append(runs, run)
+		}
+	}
+
+	if since == "" {
+		return runs, nil
+	}
+	start, err := time.ParseInLocation("2006-01-02", since, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("--since wants a date like 2026-10-01: %w", err)
+	}
+	var selected []*Run
+	for _, run := range runs {
+		if !run.StartTime.Before(start) {
+			selected = append(selected, run)
+		}
+	}
+	return selected, nil
+}
+
+func queryRuns(runs []*Run) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RUN\tSTARTED\tSTATUS\tGAMES\tSEED\tVERSION\tMODELS")
+	for _, run := range runs {
+		version := run.Version
+		if len(version) > 12 && !strings.HasSuffix(version, "-dirty") {
+			version = version[:12]
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d\t%d\t%s\t%s\n",
+			run.ID, run.StartTime.Local().Format("2006-01-02 15:04"), run.Status,
+			run.CompletedGames, run.Games, run.Seed, version, strings.Join(run.Models(), ", "))
+	}
+	return w.Flush()
+}
+
+// queryLeaderboard aggregates the runs' games the way a tournament does,
+// so statistics across runs match those of a single run
+func queryLeaderboard(db *DB, runs []*Run) error {
+	var games, interrupted []*models.GameResult
+	for _, run := range runs {
+		runGames, err := db.Games(run.ID)
+		if err != nil {
+			return err
+		}
+		games = append(games, runGames...)
+		interrupted = append(interrupted, run.InterruptedGames...)
+	}
+	if len(games) == 0 {
+		fmt.Println("No finished games in the selected runs.")
+		return nil
+	}
+
+	tournament := models.NewTournamentResult(len(games))
+	for _, result := range interrupted {
+		tournament.AddInterruptedResult(result)
+	}
+	for _, result := range games {
+		tournament.AddGameResult(result)
+	}
+
+	players := make([]*models.PlayerStats, 0, len(tournament.PlayerStats))
+	for _, stats := range tournament.PlayerStats {
+		players = append(players, stats)
+	}
+	sort.Slice(players, func(i, j int) bool {
+		if tournament.CashGame {
+			return players[i].NetChips > players[j].NetChips
+		}
+		if players[i].AvgRank != players[j].AvgRank {
+			return players[i].AvgRank < players[j].AvgRank
+		}
+		return players[i].Name < players[j].Name
+	})
+
+	fmt.Printf("%d games from %d runs\n\n", len(games), len(runs))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PLAYER\tGAMES\tWINS\tWIN%\tAVG RANK\t95% CI\tNET CHIPS\tBB/100\tWINNINGS\tINVALID%\tCOST $")
+	for _, stats := range players {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%.2f\t%.2f-%.2f\t%+d\t%.1f\t%.2f\t%.1f\t%.4f\n",
+			stats.Name, stats.TotalGames, stats.Wins, stats.WinRate, stats.AvgRank,
+			stats.AvgRankCI[0], stats.AvgRankCI[1], stats.NetChips, stats.BBPer100,
+			stats.Winnings, stats.InvalidActionRate, stats.TotalCost)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, pair := range tournament.Pairwise {
+		if pair.Significant {
+			fmt.Printf("%s is better than %s: %d-%d, p=%.3f\n",
+				pair.Better(), worse(pair), max(pair.AAhead, pair.BAhead), min(pair.AAhead, pair.BAhead), pair.PValue)
+		}
+	}
+	return nil
+}
+
+// worse returns the player a significant comparison found worse
+func worse(pair models.PairwiseResult) string {
+	if pair.Better() == pair.PlayerA {
+		return pair.PlayerB
+	}
+	return pair.PlayerA
+}
+
+func queryGames(db *DB, runs []*Run) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RUN\tGAME\tWINNER\tHANDS\tDURATION\tNOTES")
+	for _, run := range runs {
+		games, err := db.Games(run.ID)
+		if err != nil {
+			return err
+		}
+		for _, result := range append(games, run.InterruptedGames...) {
+			var notes []string
+			if result.Interrupted {
+				notes = append(notes, "interrupted")
+			}
+			if result.FallbackUsed {
+				notes = append(notes, "fallback")
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%s\t%s\n",
+				run.ID, result.GameID, result.Winner.Name, result.TotalHands, result.GameDuration, strings.Join(notes, ", "))
+		}
+	}
+	return w.Flush()
+}
+
+func queryActions(db *DB, run *Run, gameID int, player string) error {
+	games, err := db.Games(run.ID)
+	if err != nil {
+		return err
+	}
+	var game *models.GameResult
+	for _, result := range append(games, run.InterruptedGames...) {
+		if result.GameID == gameID {
+			game = result
+			break
+		}
+	}
+	if game == nil {
+		return fmt.Errorf("run %s has no game %d", run.ID, gameID)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "HAND\tTABLE\tROUND\tPLAYER\tCARDS\tACTION\tAMOUNT\tREASONING")
+	for _, hand := range game.Hands {
+		for _, action := range hand.Actions {
+			if player != "" && action.Player != player {
+				continue
+			}
+			fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\t%d\t%s\n",
+				hand.HandNumber, max(hand.Table, 1), action.Round, action.Player, strings.Join(action.Cards, " "),
+				action.Action, action.Amount, strings.Join(strings.Fields(action.Reasoning), " "))
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,192 @@
+package results
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Run statuses. A run that crashed is left running.
+const (
+	StatusRunning  = "running"
+	StatusFinished = "finished"
+	StatusStopped  = "stopped"
+)
+
+// Run is one invocation of the tournament, and what it was played with
+type Run struct {
+	ID        string    `json:"id"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Status    string    `json:"status"`
+
+	Version string   `json:"version,omitempty"` // git revision of the program
+	Args    []string `json:"args"`              // command line
+	Games   int      `json:"games"`
+	Seed    int64    `json:"seed"`
+
+	ActionDelay   time.Duration `json:"actionDelay"`
+	ActionTimeout time.Duration `json:"actionTimeout"`
+	TimeBank      time.Duration `json:"timeBank"`
+
+	Players    []models.SeatConfig          `json:"players"`
+	Definition *models.TournamentDefinition `json:"definition,omitempty"`
+
+	CompletedGames   int                  `json:"completedGames"`
+	InterruptedGames []*models.GameResult `json:"interruptedGames,omitempty"`
+	OverallWinner    string               `json:"overallWinner,omitempty"`
+}
+
+// NewRunID returns a new run ID, which sorts by start time
+func NewRunID() string {
+	return fmt.Sprintf("%s-%04x", time.Now().Format("20060102-150405"), rand.IntN(1<<16))
+}
+
+// NewRun describes a run of the configured tournament with the given
+// players
+func NewRun(id string, config *models.Config, players []models.SeatConfig) *Run {
+	return &Run{
+		ID:            id,
+		StartTime:     time.Now(),
+		Status:        StatusRunning,
+		Version:       Version(),
+		Args:          os.Args,
+		Games:         config.Games,
+		Seed:          config.Seed,
+		ActionDelay:   config.ActionDelay,
+		ActionTimeout: config.ActionTimeout,
+		TimeBank:      config.TimeBank,
+		Players:       players,
+		Definition:    config.Definition,
+	}
+}
+
+// Models returns the distinct models played in the run
+func (r *Run) Models() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, seat := range r.Players {
+		if !seen[seat.Model] {
+			seen[seat.Model] = true
+			names = append(names, seat.Model)
+		}
+	}
+	return names
+}
+
+// Version returns the git revision the program was built from, or that
+// the working tree is at when run with go run, marked -dirty if modified
+func Version() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		revision, modified := "", false
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				revision = setting.Value
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+		if revision != "" {
+			if modified {
+				revision += "-dirty"
+			}
+			return revision
+		}
+	}
+
+	out, err := exec.Command("git", "describe", "--always", "--dirty").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// Recorder saves a run's games to the database as they finish. It
+// satisfies the tournament's result exporter interface.
+type Recorder struct {
+	db  *DB
+	run *Run
+}
+
+// StartRun records the start of a run. A run already in the database, one
+// being resumed, keeps its start time and finished games.
+func (db *DB) StartRun(run *Run) (*Recorder, error) {
+	err := db.update(func(tx *bolt.Tx) error {
+		saved, err := getRun(tx, run.ID)
+		if err != nil {
+			return err
+		}
+		if saved != nil {
+			run.StartTime = saved.StartTime
+			run.CompletedGames = saved.CompletedGames
+		}
+		if _, err := tx.Bucket(gamesBucket).CreateBucketIfNotExists([]byte(run.ID)); err != nil {
+			return err
+		}
+		return putRun(tx, run)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record run: %w", err)
+	}
+	return &Recorder{db: db, run: run}, nil
+}
+
+// WriteResult saves a finished game
+func (r *Recorder) WriteResult(result *models.GameResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode game %d: %w", result.GameID, err)
+	}
+	err = r.db.update(func(tx *bolt.Tx) error {
+		games := tx.Bucket(gamesBucket).Bucket([]byte(r.run.ID))
+		if err := games.Put(gameKey(result.GameID), data); err != nil {
+			return err
+		}
+		r.run.CompletedGames = 0
+		if err := games.ForEach(func(k, v []byte) error {
+			r.run.CompletedGames++
+			return nil
+		}); err != nil {
+			return err
+		}
+		return putRun(tx, r.run)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save game %d: %w", result.GameID, err)
+	}
+	return nil
+}
+
+// WriteSummary marks the run finished, or stopped along with the games it
+// stopped. Tournament statistics are worked out from the games when
+// queried, so they aren't saved.
+func (r *Recorder) WriteSummary(tournament *models.TournamentResult) error {
+	err := r.db.update(func(tx *bolt.Tx) error {
+		r.run.EndTime = time.Now()
+		r.run.Status = StatusStopped
+		if tournament.IsComplete() {
+			r.run.Status = StatusFinished
+		}
+		r.run.InterruptedGames = tournament.InterruptedGames
+		r.run.OverallWinner = tournament.OverallWinner
+		return putRun(tx, r.run)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save run: %w", err)
+	}
+	return nil
+}
+
+// Close does nothing; the database is only open while reading or writing
+func (r *Recorder) Close() error {
+	return nil
+}
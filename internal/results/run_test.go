@@ -0,0 +1,94 @@
+package results
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+func testRun(id string) *Run {
+	config := models.DefaultConfig()
+	config.Games = 3
+	return NewRun(id, config, []models.SeatConfig{{Name: "a", Model: "test/a"}, {Name: "b", Model: "test/b"}})
+}
+
+func TestResumedRunKeepsItsGames(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "results.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := testRun("run-1")
+	first.StartTime = time.Now().Add(-time.Hour).Round(0)
+	recorder, err := db.StartRun(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []int{1, 2} {
+		if err := recorder.WriteResult(&models.GameResult{GameID: id, Winner: models.Player{Name: "a"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Saving a game again replaces it rather than counting it twice
+	if err := recorder.WriteResult(&models.GameResult{GameID: 2, Winner: models.Player{Name: "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if first.CompletedGames != 2 {
+		t.Errorf("%d games completed after rewriting one, want 2", first.CompletedGames)
+	}
+
+	// The run is stopped, then resumed under the same ID
+	if err := recorder.WriteSummary(models.NewTournamentResult(3)); err != nil {
+		t.Fatal(err)
+	}
+	if saved, err := db.Run("run-1"); err != nil || saved.Status != StatusStopped {
+		t.Fatalf("stopped run saved as %+v (%v), want status %s", saved, err, StatusStopped)
+	}
+
+	resumed := testRun("run-1")
+	recorder, err = db.StartRun(resumed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resumed.StartTime.Equal(first.StartTime) || resumed.CompletedGames != 2 {
+		t.Errorf("resumed run started %v with %d games, want %v with 2",
+			resumed.StartTime, resumed.CompletedGames, first.StartTime)
+	}
+	if resumed.Status != StatusRunning {
+		t.Errorf("resumed run is %s, want %s", resumed.Status, StatusRunning)
+	}
+
+	if err := recorder.WriteResult(&models.GameResult{GameID: 3, Winner: models.Player{Name: "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := db.Run("run-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.CompletedGames != 3 || !saved.StartTime.Equal(first.StartTime) {
+		t.Errorf("saved run has %d games from %v, want 3 from %v", saved.CompletedGames, saved.StartTime, first.StartTime)
+	}
+
+	games, err := db.Games("run-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 3 {
+		t.Fatalf("%d games saved, want 3", len(games))
+	}
+	for i, want := range []string{"a", "b", "b"} {
+		if games[i].GameID != i+1 || games[i].Winner.Name != want {
+			t.Errorf("game %d won by %s, want game %d won by %s", games[i].GameID, games[i].Winner.Name, i+1, want)
+		}
+	}
+
+	runs, err := db.Runs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 {
+		t.Errorf("%d runs saved, want the one resumed", len(runs))
+	}
+}
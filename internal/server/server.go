@@ -38,6 +38,7 @@ func (s *Server) Router() http.Handler {
 
 	// HTMX endpoints
 	mux.HandleFunc("/game-state", s.handleGameState)
+	mux.HandleFunc("/stats", s.handleStats)
 
 	// Serve home page
 	mux.HandleFunc("/", s.serveHome)
@@ -95,4 +96,12 @@ func (s *Server) handleGameState(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to encode game state", http.StatusInternalServerError)
 		return
 	}
-}
\ No newline at end of file
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.game.StyleStats()); err != nil {
+		http.Error(w, "Failed to encode stats", http.StatusInternalServerError)
+		return
+	}
+}
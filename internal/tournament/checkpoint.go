@@ -0,0 +1,124 @@
+package tournament
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/internal/game"
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// checkpointInterval is how often a changed checkpoint is written while
+// games are being played. Finished games are written straight away.
+const checkpointInterval = 5 * time.Second
+
+// Checkpoint is a tournament's progress saved to disk: every finished game,
+// and the latest snapshot of each game still being played. Multi-table
+// tournaments can't be checkpointed, as their tables would have to be saved
+// at the same moment.
+type Checkpoint struct {
+	Games   int      `json:"games"`
+	Seed    int64    `json:"seed"`
+	Format  string   `json:"format,omitempty"`
+	Players []string `json:"players"`
+	RunID   string   `json:"runId,omitempty"` // run in the results database
+
+	Completed  []*models.GameResult   `json:"completed"`
+	InProgress map[int]*game.Snapshot `json:"inProgress"`
+	SavedAt    time.Time              `json:"savedAt"`
+}
+
+// newCheckpoint starts an empty checkpoint for the configured tournament
+func newCheckpoint(config *models.Config) *Checkpoint {
+	seats := config.Seats()
+	if seats == nil {
+		seats = game.DefaultSeats()
+	}
+	checkpoint := &Checkpoint{
+		Games:      config.Games,
+		Seed:       config.Seed,
+		InProgress: make(map[int]*game.Snapshot),
+	}
+	if config.Definition != nil {
+		checkpoint.Format = config.Definition.Format
+	}
+	for _, seat := range seats {
+		checkpoint.Players = append(checkpoint.Players, seat.Name)
+	}
+	return checkpoint
+}
+
+// LoadCheckpoint reads a saved checkpoint
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	checkpoint := &Checkpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	if checkpoint.InProgress == nil {
+		checkpoint.InProgress = make(map[int]*game.Snapshot)
+	}
+	return checkpoint, nil
+}
+
+// Save writes the checkpoint to a temporary file and renames it into
+// place, so a crash while saving leaves the previous checkpoint intact
+func (c *Checkpoint) Save(path string) error {
+	c.SavedAt = time.Now()
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// matches checks that a loaded checkpoint is for the tournament now
+// configured, so resuming doesn't mix results from different runs
+func (c *Checkpoint) matches(current *Checkpoint) error {
+	switch {
+	case c.Games != current.Games:
+		return fmt.Errorf("checkpoint is for %d games, not %d", c.Games, current.Games)
+	case c.Seed != current.Seed:
+		return fmt.Errorf("checkpoint was played with seed %d, not %d", c.Seed, current.Seed)
+	case c.Format != current.Format:
+		return fmt.Errorf("checkpoint is for format %q, not %q", c.Format, current.Format)
+	case !slices.Equal(c.Players, current.Players):
+		return fmt.Errorf("checkpoint has different players")
+	}
+	return nil
+}
+
+// completed reports whether the checkpoint has a game's result
+func (c *Checkpoint) completed(gameID int) bool {
+	for _, result := range c.Completed {
+		if result.GameID == gameID {
+			return true
+		}
+	}
+	return false
+}
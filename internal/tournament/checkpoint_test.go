@@ -0,0 +1,121 @@
+package tournament
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/internal/ai/aitest"
+	"github.com/MikeLuu99/poker-arena/internal/game"
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+func checkpointConfig(path string, resume bool) *models.Config {
+	config := models.DefaultConfig()
+	config.Games = 3
+	config.NoServer = true
+	config.Seed = 17
+	config.ActionDelay = 0
+	config.OutputFile = ""
+	config.CheckpointFile = path
+	config.Resume = resume
+	return config
+}
+
+func TestResumeFromCheckpoint(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "test")
+	srv := aitest.NewServer(aitest.Fixed("call", 0))
+	defer srv.Close()
+	defer srv.UseAsOpenRouter()()
+
+	dir := t.TempDir()
+	full := filepath.Join(dir, "full.json")
+	reference, err := NewGameManager(checkpointConfig(full, false)).RunTournament()
+	if err != nil {
+		t.Fatal(err)
+	}
+	finished, err := LoadCheckpoint(full)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(finished.Completed) != 3 || len(finished.InProgress) != 0 {
+		t.Fatalf("finished checkpoint has %d games completed and %d in progress, want 3 and 0",
+			len(finished.Completed), len(finished.InProgress))
+	}
+
+	// Replay game 2 to keep a snapshot from partway through
+	path := filepath.Join(dir, "checkpoint.json")
+	gm := NewGameManager(checkpointConfig(path, false))
+	var snapshots []*game.Snapshot
+	g := gm.newGame(2)
+	g.SetCheckpoint(func(snapshot *game.Snapshot) { snapshots = append(snapshots, snapshot) })
+	g.Start()
+	if len(snapshots) < 3 {
+		t.Fatalf("game 2 took %d snapshots, want a hand in the middle", len(snapshots))
+	}
+	snapshot := snapshots[len(snapshots)/2]
+	// A game carried on from the snapshot keeps its clock, unlike one
+	// started again
+	snapshot.Elapsed = time.Hour
+
+	// Game 1 finished and game 2 underway
+	checkpoint := newCheckpoint(checkpointConfig(path, false))
+	for _, result := range finished.Completed {
+		if result.GameID == 1 {
+			checkpoint.Completed = append(checkpoint.Completed, result)
+		}
+	}
+	checkpoint.InProgress[2] = snapshot
+	if err := checkpoint.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	resumed, err := NewGameManager(checkpointConfig(path, true)).RunTournament()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resumed.IsComplete() || len(resumed.GameResults) != 3 {
+		t.Fatalf("resumed run completed %d of %d games", resumed.CompletedGames, resumed.TotalGames)
+	}
+
+	want := make(map[int]*models.GameResult)
+	for _, result := range reference.GameResults {
+		want[result.GameID] = result
+	}
+	got := make(map[int]*models.GameResult)
+	for _, result := range resumed.GameResults {
+		got[result.GameID] = result
+	}
+	if !got[1].StartTime.Equal(checkpoint.Completed[0].StartTime) {
+		t.Errorf("game 1 was played again instead of kept from the checkpoint")
+	}
+	if started := time.Since(got[2].StartTime); started < 50*time.Minute {
+		t.Errorf("game 2 started %v ago, want it carried on from its snapshot an hour in", started)
+	}
+	if len(got[2].Hands) <= len(snapshot.Hands) {
+		t.Errorf("game 2 has %d hands, want the %d before its snapshot and more", len(got[2].Hands), len(snapshot.Hands))
+	}
+	for id, result := range want {
+		if got[id].TotalHands != result.TotalHands || got[id].Winner.Name != result.Winner.Name {
+			t.Errorf("game %d: %s won after %d hands, want %s after %d",
+				id, got[id].Winner.Name, got[id].TotalHands, result.Winner.Name, result.TotalHands)
+		}
+	}
+
+	saved, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Completed) != 3 || len(saved.InProgress) != 0 {
+		t.Errorf("checkpoint after resuming has %d games completed and %d in progress, want 3 and 0",
+			len(saved.Completed), len(saved.InProgress))
+	}
+}
+
+func TestCheckpointRefusesMultiTable(t *testing.T) {
+	config := checkpointConfig(filepath.Join(t.TempDir(), "checkpoint.json"), false)
+	config.Definition = &models.TournamentDefinition{Format: "mtt", TableSize: 2, Players: testPool(4)}
+	if _, err := NewGameManager(config).RunTournament(); err == nil {
+		t.Error("checkpointing a multi-table tournament didn't fail")
+	}
+}
@@ -2,23 +2,115 @@ package tournament
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/MikeLuu99/poker-arena/pkg/models"
 )
 
-// CSVExporter handles writing game results to CSV format
+// Result file formats
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+// ResultExporter writes a tournament's results: each game as it finishes,
+// then the tournament summary once the run ends or is stopped
+type ResultExporter interface {
+	WriteResult(result *models.GameResult) error
+	WriteSummary(tournament *models.TournamentResult) error
+	Close() error
+}
+
+// ExportFormat returns the format to write results in: format if given,
+// otherwise the one named by the file's extension, CSV by default
+func ExportFormat(filename, format string) (string, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV, FormatJSON, FormatNDJSON:
+		return strings.ToLower(format), nil
+	case "":
+	default:
+		return "", fmt.Errorf("unknown output format %q (use csv, json or ndjson)", format)
+	}
+	
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON, nil
+	}
+	return FormatCSV, nil
+}
+
+// NewResultExporter creates the exporter for the output file. players is
+// the most players a game can rank, which sets the CSV's width.
+func NewResultExporter(filename, format string, players int) (ResultExporter, error) {
+	format, err := ExportFormat(filename, format)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatJSON:
+		return NewJSONExporter(filename)
+	case FormatNDJSON:
+		return NewNDJSONExporter(filename)
+	}
+	return NewCSVExporter(filename, players)
+}
+
+// exporters writes results to several exporters in turn
+type exporters []ResultExporter
+
+func (e exporters) WriteResult(result *models.GameResult) error {
+	var errs []error
+	for _, exporter := range e {
+		errs = append(errs, exporter.WriteResult(result))
+	}
+	return errors.Join(errs...)
+}
+
+func (e exporters) WriteSummary(tournament *models.TournamentResult) error {
+	var errs []error
+	for _, exporter := range e {
+		errs = append(errs, exporter.WriteSummary(tournament))
+	}
+	return errors.Join(errs...)
+}
+
+func (e exporters) Close() error {
+	var errs []error
+	for _, exporter := range e {
+		errs = append(errs, exporter.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// CSVExporter handles writing game results to CSV format. Every action,
+// with the model's reasoning, goes to a companion file next to the results
+// (results.csv -> results_actions.csv), and the tournament summary to
+// another (results_summary.csv), so the results file is one table.
 type CSVExporter struct {
-	file   *os.File
-	writer *csv.Writer
-	mu     sync.Mutex
-	header []string
+	filename      string
+	file          *os.File
+	writer        *csv.Writer
+	actionsFile   *os.File
+	actionsWriter *csv.Writer
+	mu            sync.Mutex
+	header        []string
+	players       int
 }
 
-// NewCSVExporter creates a new CSV exporter
-func NewCSVExporter(filename string) (*CSVExporter, error) {
+// NewCSVExporter creates a new CSV exporter with columns for the given
+// number of players in each game row (4 if zero)
+func NewCSVExporter(filename string, players int) (*CSVExporter, error) {
+	if players <= 0 {
+		players = 4
+	}
 	file, err := os.Create(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create CSV file: %w", err)
@@ -35,20 +127,23 @@ func NewCSVExporter(filename string) (*CSVExporter, error) {
 		"GameDuration",
 		"StartTime",
 		"EndTime",
+		"FallbackUsed",
 	}
 	
-	// Add columns for each player (assuming 4 players)
-	playerColumns := []string{"Name", "FinalChips", "Rank", "Position"}
-	for i := 1; i <= 4; i++ {
+	// Add columns for each player, in finishing order
+	playerColumns := []string{"Name", "FinalChips", "Rank", "Position", "Payout", "Tokens", "Cost"}
+	for i := 1; i <= players; i++ {
 		for _, col := range playerColumns {
 			header = append(header, fmt.Sprintf("Player%d_%s", i, col))
 		}
 	}
 	
 	exporter := &CSVExporter{
-		file:   file,
-		writer: writer,
-		header: header,
+		filename: filename,
+		file:     file,
+		writer:   writer,
+		header:   header,
+		players:  players,
 	}
 	
 	// Write header
@@ -58,6 +153,35 @@ func NewCSVExporter(filename string) (*CSVExporter, error) {
 	}
 	exporter.writer.Flush()
 	
+	actionsFile, err := os.Create(ActionsFilename(filename))
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to create actions CSV file: %w", err)
+	}
+	exporter.actionsFile = actionsFile
+	exporter.actionsWriter = csv.NewWriter(actionsFile)
+	actionsHeader := []string{
+		"GameID",
+		"Table",
+		"HandNumber",
+		"Round",
+		"Player",
+		"Cards",
+		"Board",
+		"Action",
+		"Amount",
+		"TotalBet",
+		"Reasoning",
+		"Thinking",
+		"Fallback",
+	}
+	if err := exporter.actionsWriter.Write(actionsHeader); err != nil {
+		file.Close()
+		actionsFile.Close()
+		return nil, fmt.Errorf("failed to write actions CSV header: %w", err)
+	}
+	exporter.actionsWriter.Flush()
+	
 	return exporter, nil
 }
 
@@ -75,22 +199,31 @@ func (e *CSVExporter) WriteResult(result *models.GameResult) error {
 		result.GameDuration,
 		result.StartTime.Format("2006-01-02 15:04:05"),
 		result.EndTime.Format("2006-01-02 15:04:05"),
+		fmt.Sprintf("%t", result.FallbackUsed),
 	}
 	
-	// Add player ranking data (pad to 4 players)
+	// Add player ranking data (padded to the header's players)
 	rankings := result.PlayerRankings
-	for i := 0; i < 4; i++ {
+	for i := 0; i < e.players; i++ {
 		if i < len(rankings) {
 			ranking := rankings[i]
+			tokens, cost := 0, 0.0
+			if gameStats, ok := result.PlayerStats[ranking.Player.Name]; ok {
+				tokens = gameStats.PromptTokens + gameStats.CompletionTokens
+				cost = gameStats.Cost
+			}
 			record = append(record,
 				ranking.Player.Name,
 				fmt.Sprintf("%d", ranking.Player.Chips),
 				fmt.Sprintf("%d", ranking.Rank),
 				ranking.Position,
+				fmt.Sprintf("%.2f", ranking.Payout),
+				fmt.Sprintf("%d", tokens),
+				fmt.Sprintf("%.6f", cost),
 			)
 		} else {
 			// Empty data for missing players
-			record = append(record, "", "0", "0", "")
+			record = append(record, "", "0", "0", "", "0", "0", "0")
 		}
 	}
 	
@@ -99,16 +232,80 @@ func (e *CSVExporter) WriteResult(result *models.GameResult) error {
 	}
 	
 	e.writer.Flush()
-	return e.writer.Error()
+	if err := e.writer.Error(); err != nil {
+		return err
+	}
+	return e.writeActions(result)
+}
+
+// writeActions writes every action of the game, with the board as it was
+// when the action was taken
+func (e *CSVExporter) writeActions(result *models.GameResult) error {
+	for _, hand := range result.Hands {
+		for _, action := range hand.Actions {
+			record := []string{
+				fmt.Sprintf("%d", result.GameID),
+				fmt.Sprintf("%d", max(hand.Table, 1)),
+				fmt.Sprintf("%d", hand.HandNumber),
+				action.Round,
+				action.Player,
+				strings.Join(action.Cards, " "),
+				strings.Join(boardAt(hand.Board, action.Round), " "),
+				action.Action,
+				fmt.Sprintf("%d", action.Amount),
+				fmt.Sprintf("%d", action.TotalBet),
+				action.Reasoning,
+				action.Thinking,
+				action.Fallback,
+			}
+			if err := e.actionsWriter.Write(record); err != nil {
+				return fmt.Errorf("failed to write actions CSV record: %w", err)
+			}
+		}
+	}
+	
+	e.actionsWriter.Flush()
+	return e.actionsWriter.Error()
 }
 
-// WriteSummary writes tournament summary statistics
+// ActionsFilename returns the companion actions file for a results file
+func ActionsFilename(filename string) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "_actions" + ext
+}
+
+// SummaryFilename returns the companion summary file for a results file
+func SummaryFilename(filename string) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "_summary" + ext
+}
+
+// boardAt returns the community cards visible during a betting round
+func boardAt(board []string, round string) []string {
+	visible := 0
+	switch round {
+	case "flop":
+		visible = 3
+	case "turn":
+		visible = 4
+	case "river":
+		visible = 5
+	}
+	return board[:min(visible, len(board))]
+}
+
+// WriteSummary writes tournament summary statistics to the summary file,
+// replacing anything written before
 func (e *CSVExporter) WriteSummary(tournament *models.TournamentResult) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	
-	// Write empty line separator
-	e.writer.Write([]string{})
+	file, err := os.Create(SummaryFilename(e.filename))
+	if err != nil {
+		return fmt.Errorf("failed to create summary CSV file: %w", err)
+	}
+	defer file.Close()
+	writer := csv.NewWriter(file)
 	
 	// Write summary header
 	summaryHeader := []string{
@@ -117,8 +314,9 @@ func (e *CSVExporter) WriteSummary(tournament *models.TournamentResult) error {
 		"CompletedGames", 
 		"TournamentDuration",
 		"OverallWinner",
+		"FallbackGames",
 	}
-	e.writer.Write(summaryHeader)
+	writer.Write(summaryHeader)
 	
 	// Write summary data
 	summaryData := []string{
@@ -127,44 +325,179 @@ func (e *CSVExporter) WriteSummary(tournament *models.TournamentResult) error {
 		fmt.Sprintf("%d", tournament.CompletedGames),
 		tournament.TournamentDuration,
 		tournament.OverallWinner,
+		fmt.Sprintf("%d", tournament.FallbackGames),
 	}
-	e.writer.Write(summaryData)
+	writer.Write(summaryData)
 	
 	// Write player statistics header
-	e.writer.Write([]string{})
+	writer.Write([]string{})
 	playerStatsHeader := []string{
 		"PLAYER STATISTICS",
 		"PlayerName",
+		"Parameters",
 		"TotalGames",
 		"Wins",
 		"SecondPlace", 
 		"ThirdPlace",
 		"FourthPlace",
 		"WinRate%",
+		"WinRateLow%",
+		"WinRateHigh%",
 		"AvgRank",
+		"AvgRankLow",
+		"AvgRankHigh",
 		"AvgChips",
+		"AvgChipsLow",
+		"AvgChipsHigh",
+		"Decisions",
+		"InvalidActions",
+		"DefaultedActions",
+		"FallbackDecisions",
+		"InvalidActionRate%",
+		"PromptTokens",
+		"CompletionTokens",
+		"TotalCost$",
+		"AvgLatencyMs",
+		"AvgDecisionMs",
+		"MaxDecisionMs",
+		"Timeouts",
+		"Hands",
+		"VPIP%",
+		"PFR%",
+		"ThreeBet%",
+		"AF",
+		"FoldToRaise%",
+		"WTSD%",
+		"WSD%",
+		"CBet%",
+		"AvgRaiseBB",
+		"NetChips",
+		"ChipsPerDollar",
+		"NetBB",
+		"BBPer100",
+		"Rebuys",
+		"Winnings",
+		"AvgWinnings",
+		"InTheMoney",
+		"InterruptedGames",
 	}
-	e.writer.Write(playerStatsHeader)
+	writer.Write(playerStatsHeader)
 	
 	// Write each player's statistics
 	for _, stats := range tournament.PlayerStats {
 		playerRecord := []string{
 			"",
 			stats.Name,
+			stats.Parameters,
 			fmt.Sprintf("%d", stats.TotalGames),
 			fmt.Sprintf("%d", stats.Wins),
 			fmt.Sprintf("%d", stats.SecondPlace),
 			fmt.Sprintf("%d", stats.ThirdPlace),
 			fmt.Sprintf("%d", stats.FourthPlace),
 			fmt.Sprintf("%.2f", stats.WinRate),
+			fmt.Sprintf("%.2f", stats.WinRateCI[0]),
+			fmt.Sprintf("%.2f", stats.WinRateCI[1]),
 			fmt.Sprintf("%.2f", stats.AvgRank),
+			fmt.Sprintf("%.2f", stats.AvgRankCI[0]),
+			fmt.Sprintf("%.2f", stats.AvgRankCI[1]),
 			fmt.Sprintf("%.2f", stats.AvgChips),
+			fmt.Sprintf("%.2f", stats.AvgChipsCI[0]),
+			fmt.Sprintf("%.2f", stats.AvgChipsCI[1]),
+			fmt.Sprintf("%d", stats.Decisions),
+			fmt.Sprintf("%d", stats.InvalidActions),
+			fmt.Sprintf("%d", stats.DefaultedActions),
+			fmt.Sprintf("%d", stats.FallbackDecisions),
+			fmt.Sprintf("%.2f", stats.InvalidActionRate),
+			fmt.Sprintf("%d", stats.PromptTokens),
+			fmt.Sprintf("%d", stats.CompletionTokens),
+			fmt.Sprintf("%.6f", stats.TotalCost),
+			fmt.Sprintf("%.0f", stats.AvgLatencyMs),
+			fmt.Sprintf("%.0f", stats.AvgDecisionMs),
+			fmt.Sprintf("%d", stats.MaxDecisionMs),
+			fmt.Sprintf("%d", stats.Timeouts),
+			fmt.Sprintf("%d", stats.Style.Hands),
+			fmt.Sprintf("%.1f", stats.Style.VPIP),
+			fmt.Sprintf("%.1f", stats.Style.PFR),
+			fmt.Sprintf("%.1f", stats.Style.ThreeBet),
+			fmt.Sprintf("%.2f", stats.Style.AF),
+			fmt.Sprintf("%.1f", stats.Style.FoldToRaise),
+			fmt.Sprintf("%.1f", stats.Style.WTSD),
+			fmt.Sprintf("%.1f", stats.Style.WSD),
+			fmt.Sprintf("%.1f", stats.Style.CBet),
+			fmt.Sprintf("%.1f", stats.Style.AvgRaiseBB),
+			fmt.Sprintf("%d", stats.NetChips),
+			fmt.Sprintf("%.2f", stats.ChipsPerDollar),
+			fmt.Sprintf("%.2f", stats.NetBB),
+			fmt.Sprintf("%.2f", stats.BBPer100),
+			fmt.Sprintf("%d", stats.Rebuys),
+			fmt.Sprintf("%.2f", stats.Winnings),
+			fmt.Sprintf("%.2f", stats.AvgWinnings),
+			fmt.Sprintf("%d", stats.InTheMoney),
+			fmt.Sprintf("%d", stats.InterruptedGames),
 		}
-		e.writer.Write(playerRecord)
+		writer.Write(playerRecord)
 	}
 	
-	e.writer.Flush()
-	return e.writer.Error()
+	// Write pairwise comparisons
+	writer.Write([]string{})
+	writer.Write([]string{
+		"PAIRWISE COMPARISONS",
+		"PlayerA",
+		"PlayerB",
+		"AAhead",
+		"BAhead",
+		"Ties",
+		"PValue",
+		"Significant",
+	})
+	for _, pair := range tournament.Pairwise {
+		writer.Write([]string{
+			"",
+			pair.PlayerA,
+			pair.PlayerB,
+			fmt.Sprintf("%d", pair.AAhead),
+			fmt.Sprintf("%d", pair.BAhead),
+			fmt.Sprintf("%d", pair.Ties),
+			fmt.Sprintf("%.4f", pair.PValue),
+			fmt.Sprintf("%t", pair.Significant),
+		})
+	}
+	
+	// Write head-to-head matrices
+	if h2h := tournament.HeadToHead; h2h != nil {
+		writeMatrix(writer, "HEAD-TO-HEAD CHIPS WON (row from column)", h2h.Players, func(row, column string) string {
+			return fmt.Sprintf("%.0f", h2h.ChipsWon[row][column])
+		})
+		writeMatrix(writer, "HEAD-TO-HEAD ELIMINATIONS (row knocked out column)", h2h.Players, func(row, column string) string {
+			return fmt.Sprintf("%d", h2h.Eliminations[row][column])
+		})
+		writeMatrix(writer, "HEAD-TO-HEAD FINISHED AHEAD (row ahead of column, of games together)", h2h.Players, func(row, column string) string {
+			return fmt.Sprintf("%d/%d", h2h.FinishedAhead[row][column], h2h.Games[row][column])
+		})
+	}
+	
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write summary CSV file: %w", err)
+	}
+	return file.Close()
+}
+
+// writeMatrix writes a player-by-player section, leaving the diagonal blank
+func writeMatrix(writer *csv.Writer, title string, players []string, cell func(row, column string) string) {
+	writer.Write([]string{})
+	writer.Write(append([]string{title, "Player"}, players...))
+	for _, row := range players {
+		record := []string{"", row}
+		for _, column := range players {
+			value := ""
+			if row != column {
+				value = cell(row, column)
+			}
+			record = append(record, value)
+		}
+		writer.Write(record)
+	}
 }
 
 // Close closes the CSV file and flushes any remaining data
@@ -175,7 +508,13 @@ func (e *CSVExporter) Close() error {
 	if e.writer != nil {
 		e.writer.Flush()
 	}
+	if e.actionsWriter != nil {
+		e.actionsWriter.Flush()
+	}
 	
+	if e.actionsFile != nil {
+		e.actionsFile.Close()
+	}
 	if e.file != nil {
 		return e.file.Close()
 	}
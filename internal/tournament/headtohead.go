@@ -0,0 +1,117 @@
+package tournament
+
+import (
+	"sort"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// ComputeHeadToHead compares every pair of players directly over the given
+// games: chips won from each other, knockouts, and finishing order. Overall
+// win rates can hide that A beats B, B beats C and C beats A; this doesn't.
+func ComputeHeadToHead(results []*models.GameResult) *models.HeadToHead {
+	h2h := &models.HeadToHead{
+		Games:         make(map[string]map[string]int),
+		ChipsWon:      make(map[string]map[string]float64),
+		Eliminations:  make(map[string]map[string]int),
+		FinishedAhead: make(map[string]map[string]int),
+	}
+
+	seen := make(map[string]bool)
+	for _, result := range results {
+		for _, a := range result.PlayerRankings {
+			if !seen[a.Player.Name] {
+				seen[a.Player.Name] = true
+				h2h.Players = append(h2h.Players, a.Player.Name)
+			}
+			for _, b := range result.PlayerRankings {
+				if a.Player.Name == b.Player.Name {
+					continue
+				}
+				addCount(h2h.Games, a.Player.Name, b.Player.Name, 1)
+				if a.Rank < b.Rank {
+					addCount(h2h.FinishedAhead, a.Player.Name, b.Player.Name, 1)
+				}
+			}
+		}
+
+		for _, hand := range result.Hands {
+			transfers := handTransfers(hand)
+			for from, to := range transfers {
+				for winner, chips := range to {
+					addChips(h2h.ChipsWon, winner, from, chips)
+					addChips(h2h.ChipsWon, from, winner, -chips)
+				}
+			}
+			for _, busted := range hand.Eliminated {
+				if eliminator := biggestWinner(transfers[busted]); eliminator != "" {
+					addCount(h2h.Eliminations, eliminator, busted, 1)
+				}
+			}
+		}
+	}
+
+	sort.Strings(h2h.Players)
+	return h2h
+}
+
+// handTransfers splits each loser's net loss in a hand among the players who
+// came out ahead, in proportion to their net gain: [loser][winner] = chips
+func handTransfers(hand models.HandRecord) map[string]map[string]float64 {
+	net := make(map[string]int)
+	for _, action := range hand.Actions {
+		net[action.Player] -= action.Amount
+	}
+	for name, chips := range hand.Winnings {
+		net[name] += chips
+	}
+
+	gained := 0
+	for _, chips := range net {
+		if chips > 0 {
+			gained += chips
+		}
+	}
+
+	transfers := make(map[string]map[string]float64)
+	if gained == 0 {
+		return transfers
+	}
+	for loser, lost := range net {
+		if lost >= 0 {
+			continue
+		}
+		transfers[loser] = make(map[string]float64)
+		for winner, won := range net {
+			if won > 0 {
+				transfers[loser][winner] = float64(-lost) * float64(won) / float64(gained)
+			}
+		}
+	}
+	return transfers
+}
+
+// biggestWinner returns who took the most chips, breaking ties by name
+func biggestWinner(to map[string]float64) string {
+	best := ""
+	for name, chips := range to {
+		if best == "" || chips > to[best] || (chips == to[best] && name < best) {
+			best = name
+		}
+	}
+	return best
+}
+
+func addCount(matrix map[string]map[string]int, row, column string, n int) {
+	if matrix[row] == nil {
+		matrix[row] = make(map[string]int)
+	}
+	matrix[row][column] += n
+}
+
+func addChips(matrix map[string]map[string]float64, row, column string, chips float64) {
+	if matrix[row] == nil {
+		matrix[row] = make(map[string]float64)
+	}
+	matrix[row][column] += chips
+}
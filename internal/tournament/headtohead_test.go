@@ -0,0 +1,111 @@
+package tournament
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+func TestComputeHeadToHead(t *testing.T) {
+	rankings := func(names ...string) []models.PlayerRanking {
+		var rankings []models.PlayerRanking
+		for i, name := range names {
+			rankings = append(rankings, models.PlayerRanking{Player: models.Player{Name: name}, Rank: i + 1})
+		}
+		return rankings
+	}
+	results := []*models.GameResult{
+		{
+			PlayerRankings: rankings("a", "b", "c"),
+			Hands: []models.HandRecord{
+				{
+					// c raises, a calls and b folds the big blind: c takes
+					// 40 from a and 10 from b
+					HandNumber: 1,
+					Actions: []models.ActionRecord{
+						{Player: "a", Action: "small_blind", Amount: 5},
+						{Player: "b", Action: "big_blind", Amount: 10},
+						{Player: "c", Action: "raise", Amount: 40},
+						{Player: "a", Action: "call", Amount: 35},
+						{Player: "b", Action: "fold"},
+					},
+					Winnings: map[string]int{"c": 90},
+				},
+				{
+					// c is all in and a and b split the pot: each takes 15
+					// from c, and a is credited with the knockout by name
+					HandNumber: 2,
+					Actions: []models.ActionRecord{
+						{Player: "c", Action: "raise", Amount: 30},
+						{Player: "a", Action: "call", Amount: 30},
+						{Player: "b", Action: "call", Amount: 30},
+					},
+					Winnings:   map[string]int{"a": 45, "b": 45},
+					Eliminated: []string{"c"},
+				},
+			},
+		},
+		{PlayerRankings: rankings("b", "a")},
+	}
+
+	h2h := ComputeHeadToHead(results)
+	if fmt.Sprint(h2h.Players) != "[a b c]" {
+		t.Errorf("players %v, want [a b c]", h2h.Players)
+	}
+
+	counts := []struct {
+		name     string
+		matrix   map[string]map[string]int
+		row, col string
+		want     int
+	}{
+		{"games", h2h.Games, "a", "b", 2},
+		{"games", h2h.Games, "b", "a", 2},
+		{"games", h2h.Games, "a", "c", 1},
+		{"finished ahead", h2h.FinishedAhead, "a", "b", 1},
+		{"finished ahead", h2h.FinishedAhead, "b", "a", 1},
+		{"finished ahead", h2h.FinishedAhead, "b", "c", 1},
+		{"finished ahead", h2h.FinishedAhead, "c", "a", 0},
+		{"eliminations", h2h.Eliminations, "a", "c", 1},
+		{"eliminations", h2h.Eliminations, "b", "c", 0},
+		{"eliminations", h2h.Eliminations, "c", "a", 0},
+	}
+	for _, c := range counts {
+		if got := c.matrix[c.row][c.col]; got != c.want {
+			t.Errorf("%s[%s][%s] = %d, want %d", c.name, c.row, c.col, got, c.want)
+		}
+	}
+
+	chips := []struct {
+		row, col string
+		want     float64
+	}{
+		{"c", "a", 25},
+		{"a", "c", -25},
+		{"b", "c", 5},
+		{"c", "b", -5},
+		{"a", "b", 0},
+	}
+	for _, c := range chips {
+		if got := h2h.ChipsWon[c.row][c.col]; got != c.want {
+			t.Errorf("chipsWon[%s][%s] = %v, want %v", c.row, c.col, got, c.want)
+		}
+	}
+}
+
+func TestHandTransfersSplitsByGain(t *testing.T) {
+	// a loses 60; b nets 40 and c nets 20 of it
+	hand := models.HandRecord{
+		Actions: []models.ActionRecord{
+			{Player: "a", Amount: 60},
+			{Player: "b", Amount: 60},
+			{Player: "c", Amount: 30},
+		},
+		Winnings: map[string]int{"b": 100, "c": 50},
+	}
+	transfers := handTransfers(hand)
+	if len(transfers) != 1 || transfers["a"]["b"] != 40 || transfers["a"]["c"] != 20 {
+		t.Errorf("transfers %v, want a giving 40 to b and 20 to c", transfers)
+	}
+}
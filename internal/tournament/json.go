@@ -0,0 +1,98 @@
+package tournament
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// JSONExporter writes the whole tournament as one JSON document: the
+// summary, player statistics and every game's result with its hands and
+// actions. The file is written when the summary is, at the end of the run.
+type JSONExporter struct {
+	file *os.File
+	mu   sync.Mutex
+}
+
+// NewJSONExporter creates a JSON exporter
+func NewJSONExporter(filename string) (*JSONExporter, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JSON file: %w", err)
+	}
+	return &JSONExporter{file: file}, nil
+}
+
+// WriteResult does nothing: finished games are written with the summary
+func (e *JSONExporter) WriteResult(result *models.GameResult) error {
+	return nil
+}
+
+// WriteSummary writes the tournament, replacing anything written before
+func (e *JSONExporter) WriteSummary(tournament *models.TournamentResult) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	data, err := json.MarshalIndent(tournament, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tournament: %w", err)
+	}
+
+	if err := e.file.Truncate(0); err != nil {
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+	if _, err := e.file.WriteAt(append(data, '\n'), 0); err != nil {
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+	return nil
+}
+
+// Close closes the JSON file
+func (e *JSONExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.file.Close()
+}
+
+// NDJSONExporter streams each game's result, with its hands and actions,
+// as one JSON line as soon as the game finishes, so a run can be read
+// while it is still going and a crash loses nothing already written
+type NDJSONExporter struct {
+	file    *os.File
+	encoder *json.Encoder
+	mu      sync.Mutex
+}
+
+// NewNDJSONExporter creates an NDJSON exporter
+func NewNDJSONExporter(filename string) (*NDJSONExporter, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NDJSON file: %w", err)
+	}
+	return &NDJSONExporter{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// WriteResult writes a game's result as one line
+func (e *NDJSONExporter) WriteResult(result *models.GameResult) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.encoder.Encode(result); err != nil {
+		return fmt.Errorf("failed to write NDJSON record: %w", err)
+	}
+	return nil
+}
+
+// WriteSummary does nothing, so every line of the file is a game result
+func (e *NDJSONExporter) WriteSummary(tournament *models.TournamentResult) error {
+	return nil
+}
+
+// Close closes the NDJSON file
+func (e *NDJSONExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.file.Close()
+}
@@ -0,0 +1,128 @@
+package tournament
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+func testResult(id int, winner string) *models.GameResult {
+	return &models.GameResult{
+		GameID:     id,
+		Winner:     models.Player{Name: winner, Chips: 80},
+		TotalHands: 12,
+		Hands: []models.HandRecord{{
+			HandNumber: 1,
+			Actions:    []models.ActionRecord{{Round: "preflop", Player: winner, Action: "raise", Amount: 20}},
+			Winnings:   map[string]int{winner: 30},
+		}},
+		PlayerRankings: []models.PlayerRanking{{Player: models.Player{Name: winner, Chips: 80}, Rank: 1}},
+	}
+}
+
+// readLines decodes every line of an NDJSON file as a game result
+func readLines(t *testing.T, path string) []*models.GameResult {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var results []*models.GameResult
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		result := &models.GameResult{}
+		if err := json.Unmarshal(scanner.Bytes(), result); err != nil {
+			t.Fatalf("line %d is not a game result: %v", len(results)+1, err)
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return results
+}
+
+func TestNDJSONExporterStreamsResults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.ndjson")
+	exporter, err := NewNDJSONExporter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer exporter.Close()
+
+	winners := []string{"alice", "bob", "carol"}
+	for i, winner := range winners {
+		if err := exporter.WriteResult(testResult(i+1, winner)); err != nil {
+			t.Fatal(err)
+		}
+
+		// Each game is on disk as soon as it is written, one line apiece
+		lines := readLines(t, path)
+		if len(lines) != i+1 {
+			t.Fatalf("%d lines after %d results", len(lines), i+1)
+		}
+		last := lines[i]
+		if last.GameID != i+1 || last.Winner.Name != winner || len(last.Hands) != 1 || last.Hands[0].Winnings[winner] != 30 {
+			t.Errorf("line %d = game %d won by %s, want game %d won by %s with its hand", i+1, last.GameID, last.Winner.Name, i+1, winner)
+		}
+	}
+
+	// The summary adds nothing, so every line stays a game result
+	if err := exporter.WriteSummary(models.NewTournamentResult(len(winners))); err != nil {
+		t.Fatal(err)
+	}
+	if lines := readLines(t, path); len(lines) != len(winners) {
+		t.Errorf("%d lines after the summary, want %d", len(lines), len(winners))
+	}
+}
+
+func TestJSONExporterRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	exporter, err := NewJSONExporter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tournament := models.NewTournamentResult(2)
+	for i, winner := range []string{"alice", "bob"} {
+		result := testResult(i+1, winner)
+		if err := exporter.WriteResult(result); err != nil {
+			t.Fatal(err)
+		}
+		tournament.AddGameResult(result)
+		// Each summary replaces the last, so the file always parses
+		if err := exporter.WriteSummary(tournament); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := exporter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded models.TournamentResult
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("JSON output doesn't parse as a tournament: %v", err)
+	}
+	if loaded.TotalGames != 2 || loaded.CompletedGames != 2 || len(loaded.GameResults) != 2 {
+		t.Fatalf("loaded %d of %d games with %d results, want 2 of 2", loaded.CompletedGames, loaded.TotalGames, len(loaded.GameResults))
+	}
+	for i, result := range loaded.GameResults {
+		want := tournament.GameResults[i]
+		if result.GameID != want.GameID || result.Winner.Name != want.Winner.Name || len(result.Hands) != 1 {
+			t.Errorf("game %d = %+v, want %+v", i+1, result, want)
+		}
+	}
+	if loaded.OverallWinner != tournament.OverallWinner {
+		t.Errorf("overall winner %s, want %s", loaded.OverallWinner, tournament.OverallWinner)
+	}
+}
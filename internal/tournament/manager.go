@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/MikeLuu99/poker-arena/internal/game"
+	"github.com/MikeLuu99/poker-arena/internal/rating"
+	"github.com/MikeLuu99/poker-arena/internal/results"
 	"github.com/MikeLuu99/poker-arena/internal/server"
 	"github.com/MikeLuu99/poker-arena/pkg/models"
 )
@@ -18,9 +20,19 @@ import (
 type GameManager struct {
 	config     *models.Config
 	tournament *models.TournamentResult
-	exporter   *CSVExporter
+	exporter   ResultExporter
+	ratings    *rating.Store // nil unless a ratings file is configured
+	database   *results.DB   // nil unless a results database is configured
+	tables     [][]models.SeatConfig // seats for each game when the pool is larger than a table
+	multiTable *MultiTable           // the multi-table tournament in progress, if any
+	games      []*game.Game          // single-table games, stopped with the tournament
 	servers    []*http.Server
 	mu         sync.RWMutex
+	
+	checkpoint      *Checkpoint // progress saved to the checkpoint file, nil if not saving
+	checkpointErr   error       // why the checkpoint couldn't be resumed
+	checkpointDirty bool        // changed since last saved
+	checkpointMu    sync.Mutex
 	ctx        context.Context
 	cancel     context.CancelFunc
 }
@@ -30,13 +42,64 @@ func NewGameManager(config *models.Config) *GameManager {
 	ctx, cancel := context.WithCancel(context.Background())
 	
 	tournament := models.NewTournamentResult(config.Games)
+	if config.Definition != nil {
+		tournament.Payouts = config.Definition.Payouts
+	}
+	
+	var ratings *rating.Store
+	if config.RatingsFile != "" {
+		var err error
+		ratings, err = rating.Load(config.RatingsFile)
+		if err != nil {
+			log.Printf("Warning: Failed to load ratings, not rating this run: %v", err)
+		}
+	}
 	
-	var exporter *CSVExporter
+	var database *results.DB
+	if config.DatabaseFile != "" {
+		var err error
+		database, err = results.Open(config.DatabaseFile)
+		if err != nil {
+			log.Printf("Warning: %v, not recording this run", err)
+		}
+	}
+	
+	tables := ScheduleTables(config)
+	
+	var exporter ResultExporter
 	if config.OutputFile != "" {
+		// A row per game has room for every player a game can rank
+		players := len(game.DefaultSeats())
+		if seats := config.Seats(); seats != nil {
+			players = len(seats)
+		}
+		if tables != nil {
+			players = len(tables[0])
+		}
 		var err error
-		exporter, err = NewCSVExporter(config.OutputFile)
+		exporter, err = NewResultExporter(config.OutputFile, config.OutputFormat, players)
 		if err != nil {
-			log.Printf("Warning: Failed to create CSV exporter: %v", err)
+			log.Printf("Warning: Failed to create result exporter: %v", err)
+			exporter = nil
+		}
+	}
+	
+	var checkpoint *Checkpoint
+	var checkpointErr error
+	if config.CheckpointFile != "" {
+		checkpoint = newCheckpoint(config)
+		if config.Definition.IsMultiTable() {
+			checkpointErr = fmt.Errorf("checkpoints are not supported for multi-table tournaments")
+		} else if config.Resume {
+			saved, err := LoadCheckpoint(config.CheckpointFile)
+			if err == nil {
+				err = saved.matches(checkpoint)
+			}
+			if err != nil {
+				checkpointErr = fmt.Errorf("cannot resume: %w", err)
+			} else {
+				checkpoint = saved
+			}
 		}
 	}
 	
@@ -44,40 +107,186 @@ func NewGameManager(config *models.Config) *GameManager {
 		config:     config,
 		tournament: tournament,
 		exporter:   exporter,
+		ratings:    ratings,
+		database:   database,
+		tables:     tables,
 		servers:    make([]*http.Server, 0),
 		ctx:        ctx,
 		cancel:     cancel,
+		
+		checkpoint:      checkpoint,
+		checkpointErr:   checkpointErr,
+		checkpointDirty: true,
 	}
 }
 
 // RunTournament runs the configured number of games
 func (gm *GameManager) RunTournament() (*models.TournamentResult, error) {
+	if gm.checkpointErr != nil {
+		return gm.tournament, gm.checkpointErr
+	}
+	if gm.database != nil {
+		gm.startRun()
+	}
+	if gm.exporter != nil {
+		defer gm.closeExporter()
+	}
+	if gm.checkpoint != nil {
+		gm.resumeCompleted()
+		done := make(chan struct{})
+		go gm.saveCheckpoints(done)
+		defer func() {
+			close(done)
+			gm.saveCheckpoint()
+		}()
+	}
+	
+	if gm.config.Definition.IsMultiTable() {
+		return gm.runMultiTables()
+	}
 	if gm.config.Games == 1 {
 		return gm.runSingleGame()
 	}
 	return gm.runParallelGames()
 }
 
+// newGame creates a game with the configured or scheduled seats, deck
+// seed, pace and time limits. When checkpointing, it carries on the game
+// from its saved snapshot, if any, and saves it at every hand.
+func (gm *GameManager) newGame(gameID int) *game.Game {
+	seats := gm.config.Seats()
+	if gm.tables != nil {
+		seats = gm.tables[gameID-1]
+	}
+	if gm.checkpoint == nil {
+		return gm.setUpGame(game.NewGameWithSeats(gameID, seats), gm.config.GameSeed(gameID))
+	}
+	
+	gm.checkpointMu.Lock()
+	snapshot := gm.checkpoint.InProgress[gameID]
+	gm.checkpointMu.Unlock()
+	
+	var g *game.Game
+	if snapshot != nil {
+		g = gm.setUpGame(game.NewGameWithSeats(gameID, snapshot.Seats), gm.config.GameSeed(gameID))
+		g.Restore(snapshot)
+		if gm.config.Verbose {
+			log.Printf("Game %d resumes at hand %d", gameID, snapshot.State.HandNumber)
+		}
+	} else {
+		g = gm.setUpGame(game.NewGameWithSeats(gameID, seats), gm.config.GameSeed(gameID))
+	}
+	g.SetCheckpoint(gm.saveSnapshot)
+	return g
+}
+
+// setUpGame applies the deck seed, pace, time limits and cash game
+// settings to a new game
+func (gm *GameManager) setUpGame(g *game.Game, seed int64) *game.Game {
+	g.SetSeed(seed)
+	g.SetActionDelay(gm.config.ActionDelay)
+	g.SetTimeControl(gm.config.ActionTimeout, gm.config.TimeBank)
+	g.SetCashGame(gm.config.Definition.CashGame())
+	return g
+}
+
+// runMultiTables plays each game as a multi-table tournament. Tournaments
+// run one after another; each one's tables run in parallel.
+func (gm *GameManager) runMultiTables() (*models.TournamentResult, error) {
+	def := gm.config.Definition
+	if gm.config.WithServers {
+		log.Printf("Warning: web servers are not available for multi-table tournaments")
+	}
+	if gm.config.Verbose {
+		go gm.reportProgress()
+	}
+	
+	for gameID := 1; gameID <= gm.config.Games; gameID++ {
+		if gm.ctx.Err() != nil {
+			return gm.tournament, gm.ctx.Err()
+		}
+		// Tables get distinct seeds that don't repeat across tournaments
+		mt := NewMultiTable(gameID, def.Players, def.TableSize, func(number int, seats []models.SeatConfig) *game.Game {
+			seed := gm.config.GameSeed(gameID)
+			if seed != 0 {
+				seed += int64(number-1) * int64(gm.config.Games)
+			}
+			return gm.setUpGame(game.NewGameWithSeats(number, seats), seed)
+		})
+		gm.mu.Lock()
+		gm.multiTable = mt
+		gm.mu.Unlock()
+		
+		result := mt.Run()
+		if gm.ctx.Err() != nil {
+			if interrupted := mt.InterruptedResult(gm.tournament.Payouts); interrupted != nil {
+				gm.mu.Lock()
+				gm.tournament.AddInterruptedResult(interrupted)
+				gm.mu.Unlock()
+			}
+			return gm.tournament, gm.ctx.Err()
+		}
+		if result == nil {
+			return gm.tournament, nil
+		}
+		models.PayRankings(result.PlayerRankings, gm.tournament.Payouts)
+		
+		gm.mu.Lock()
+		gm.tournament.AddGameResult(result)
+		gm.tournament.HeadToHead = ComputeHeadToHead(gm.tournament.GameResults)
+		gm.mu.Unlock()
+		gm.rateGame(result)
+		
+		if gm.exporter != nil {
+			if err := gm.exporter.WriteResult(result); err != nil {
+				log.Printf("Error writing results: %v", err)
+			}
+		}
+		if gm.config.Verbose {
+			log.Printf("Tournament %d completed: Winner %s (%d hands across %d tables, %s)",
+				gameID, result.Winner.Name, result.TotalHands, len(mt.tables), result.GameDuration)
+		}
+	}
+	
+	return gm.tournament, nil
+}
+
 // runSingleGame runs a single game (existing behavior)
 func (gm *GameManager) runSingleGame() (*models.TournamentResult, error) {
 	if gm.config.Verbose {
 		log.Println("Starting single game...")
 	}
+	if gm.finished(1) {
+		return gm.tournament, nil
+	}
 	
-	g := game.NewGameWithID(1)
+	g := gm.newGame(1)
+	gm.mu.Lock()
+	gm.games = []*game.Game{g}
+	gm.mu.Unlock()
 	result := g.Start()
 	
+	if gm.ctx.Err() != nil {
+		if interrupted := g.InterruptedResult(gm.tournament.Payouts); interrupted != nil {
+			gm.tournament.AddInterruptedResult(interrupted)
+		}
+		return gm.tournament, gm.ctx.Err()
+	}
+	
 	if result != nil {
 		// Populate additional fields
 		result.StartTime = g.GetStartTime()
 		result.EndTime = time.Now()
-		result.PlayerRankings = gm.calculatePlayerRankings(result)
+		models.PayRankings(result.PlayerRankings, gm.tournament.Payouts)
 		
 		gm.tournament.AddGameResult(result)
+		gm.tournament.HeadToHead = ComputeHeadToHead(gm.tournament.GameResults)
+		gm.rateGame(result)
+		gm.saveResult(result)
 		
 		if gm.exporter != nil {
 			if err := gm.exporter.WriteResult(result); err != nil {
-				log.Printf("Error writing to CSV: %v", err)
+				log.Printf("Error writing results: %v", err)
 			}
 		}
 	}
@@ -94,11 +303,16 @@ func (gm *GameManager) runParallelGames() (*models.TournamentResult, error) {
 	// Channel to collect results
 	resultsChan := make(chan *models.GameResult, gm.config.Games)
 	
-	// Create all games first
-	games := make([]*game.Game, gm.config.Games)
-	for i := 0; i < gm.config.Games; i++ {
-		games[i] = game.NewGameWithID(i + 1)
+	// Create all games first, skipping any finished before a resume
+	games := make([]*game.Game, 0, gm.config.Games)
+	for gameID := 1; gameID <= gm.config.Games; gameID++ {
+		if !gm.finished(gameID) {
+			games = append(games, gm.newGame(gameID))
+		}
 	}
+	gm.mu.Lock()
+	gm.games = games
+	gm.mu.Unlock()
 	
 	// Start web servers if requested
 	if gm.config.WithServers {
@@ -117,7 +331,7 @@ func (gm *GameManager) runParallelGames() (*models.TournamentResult, error) {
 	}
 	
 	// Launch all games
-	for i, g := range games {
+	for _, g := range games {
 		wg.Add(1)
 		go func(g *game.Game, gameID int) {
 			defer wg.Done()
@@ -140,11 +354,19 @@ func (gm *GameManager) runParallelGames() (*models.TournamentResult, error) {
 			// Run the game
 			result := g.Start()
 			
-			if result != nil && gm.ctx.Err() == nil {
+			// A stopped game is scored as it stands
+			if gm.ctx.Err() != nil {
+				if interrupted := g.InterruptedResult(gm.tournament.Payouts); interrupted != nil {
+					resultsChan <- interrupted
+				}
+				return
+			}
+			
+			if result != nil {
 				// Populate additional fields
 				result.StartTime = g.GetStartTime()
 				result.EndTime = time.Now()
-				result.PlayerRankings = gm.calculatePlayerRankings(result)
+				models.PayRankings(result.PlayerRankings, gm.tournament.Payouts)
 				
 				resultsChan <- result
 				
@@ -153,7 +375,7 @@ func (gm *GameManager) runParallelGames() (*models.TournamentResult, error) {
 						gameID, result.Winner.Name, result.TotalHands, result.GameDuration)
 				}
 			}
-		}(g, i+1)
+		}(g, g.ID)
 	}
 	
 	// Close results channel when all workers are done
@@ -162,26 +384,37 @@ func (gm *GameManager) runParallelGames() (*models.TournamentResult, error) {
 		close(resultsChan)
 	}()
 	
-	// Collect results
+	// Collect results. Once stopped, games send what they had and exit, so
+	// keep collecting until they're all done.
 	for result := range resultsChan {
-		select {
-		case <-gm.ctx.Done():
-			return gm.tournament, gm.ctx.Err()
-		default:
+		if result.Interrupted {
+			gm.mu.Lock()
+			gm.tournament.AddInterruptedResult(result)
+			gm.mu.Unlock()
+			continue
+		}
+		if gm.ctx.Err() != nil {
+			continue
 		}
 		
 		gm.mu.Lock()
 		gm.tournament.AddGameResult(result)
+		gm.tournament.HeadToHead = ComputeHeadToHead(gm.tournament.GameResults)
 		gm.mu.Unlock()
+		gm.rateGame(result)
+		gm.saveResult(result)
 		
 		// Write to CSV
 		if gm.exporter != nil {
 			if err := gm.exporter.WriteResult(result); err != nil {
-				log.Printf("Error writing to CSV: %v", err)
+				log.Printf("Error writing results: %v", err)
 			}
 		}
 	}
 	
+	if gm.ctx.Err() != nil {
+		return gm.tournament, gm.ctx.Err()
+	}
 	if gm.config.Verbose && gm.tournament.IsComplete() {
 		log.Printf("Tournament completed! Overall winner: %s", gm.tournament.OverallWinner)
 	}
@@ -189,38 +422,99 @@ func (gm *GameManager) runParallelGames() (*models.TournamentResult, error) {
 	return gm.tournament, nil
 }
 
-// calculatePlayerRankings determines final rankings based on chip count
-func (gm *GameManager) calculatePlayerRankings(result *models.GameResult) []models.PlayerRanking {
-	// Sort players by chip count (descending)
-	players := make([]models.Player, len(result.AllPlayers))
-	copy(players, result.AllPlayers)
-	
-	// Simple bubble sort by chips
-	for i := 0; i < len(players)-1; i++ {
-		for j := 0; j < len(players)-1-i; j++ {
-			if players[j].Chips < players[j+1].Chips {
-				players[j], players[j+1] = players[j+1], players[j]
+// resumeCompleted adds the games finished before the checkpoint was saved
+func (gm *GameManager) resumeCompleted() {
+	if !gm.config.Resume {
+		return
+	}
+	log.Printf("Resuming from %s: %d games finished, %d in progress",
+		gm.config.CheckpointFile, len(gm.checkpoint.Completed), len(gm.checkpoint.InProgress))
+	
+	for _, result := range gm.checkpoint.Completed {
+		gm.tournament.AddGameResult(result)
+		if gm.exporter != nil {
+			if err := gm.exporter.WriteResult(result); err != nil {
+				log.Printf("Error writing results: %v", err)
 			}
 		}
 	}
+	gm.tournament.HeadToHead = ComputeHeadToHead(gm.tournament.GameResults)
+}
+
+// finished reports whether a game was finished before a resume
+func (gm *GameManager) finished(gameID int) bool {
+	if gm.checkpoint == nil {
+		return false
+	}
+	gm.checkpointMu.Lock()
+	defer gm.checkpointMu.Unlock()
+	return gm.checkpoint.completed(gameID)
+}
+
+// saveSnapshot keeps a game's latest snapshot for the next checkpoint
+func (gm *GameManager) saveSnapshot(snapshot *game.Snapshot) {
+	gm.checkpointMu.Lock()
+	defer gm.checkpointMu.Unlock()
+	gm.checkpoint.InProgress[snapshot.ID] = snapshot
+	gm.checkpointDirty = true
+}
+
+// saveResult records a finished game in the checkpoint and saves it
+func (gm *GameManager) saveResult(result *models.GameResult) {
+	if gm.checkpoint == nil {
+		return
+	}
+	gm.checkpointMu.Lock()
+	gm.checkpoint.Completed = append(gm.checkpoint.Completed, result)
+	delete(gm.checkpoint.InProgress, result.GameID)
+	gm.checkpointDirty = true
+	gm.checkpointMu.Unlock()
+	gm.saveCheckpoint()
+}
+
+// saveCheckpoints saves the checkpoint periodically until done is closed
+func (gm *GameManager) saveCheckpoints(done <-chan struct{}) {
+	ticker := time.NewTicker(checkpointInterval)
+	defer ticker.Stop()
 	
-	rankings := make([]models.PlayerRanking, len(players))
-	positions := []string{"Winner", "Runner-up", "3rd Place", "4th Place"}
-	
-	for i, player := range players {
-		position := "4th Place"
-		if i < len(positions) {
-			position = positions[i]
-		}
-		
-		rankings[i] = models.PlayerRanking{
-			Player:   player,
-			Rank:     i + 1,
-			Position: position,
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			gm.saveCheckpoint()
 		}
 	}
-	
-	return rankings
+}
+
+// saveCheckpoint writes the checkpoint if it changed since last saved
+func (gm *GameManager) saveCheckpoint() {
+	gm.checkpointMu.Lock()
+	defer gm.checkpointMu.Unlock()
+	if !gm.checkpointDirty {
+		return
+	}
+	if err := gm.checkpoint.Save(gm.config.CheckpointFile); err != nil {
+		log.Printf("Error saving checkpoint: %v", err)
+		return
+	}
+	gm.checkpointDirty = false
+}
+
+// rateGame updates and saves the ratings with a finished game
+func (gm *GameManager) rateGame(result *models.GameResult) {
+	if gm.ratings == nil {
+		return
+	}
+	gm.ratings.AddGame(result)
+	if err := gm.ratings.Save(); err != nil {
+		log.Printf("Error saving ratings: %v", err)
+	}
+}
+
+// Ratings returns the persistent ratings, or nil if none are configured
+func (gm *GameManager) Ratings() *rating.Store {
+	return gm.ratings
 }
 
 // reportProgress reports tournament progress periodically
@@ -318,17 +612,75 @@ func (gm *GameManager) stopWebServers() {
 	}
 }
 
+// startRun records the run in the results database and adds it to the
+// exporters. A resumed run keeps the run ID saved in its checkpoint, so
+// its games stay together.
+func (gm *GameManager) startRun() {
+	id := results.NewRunID()
+	if gm.checkpoint != nil {
+		gm.checkpointMu.Lock()
+		if gm.checkpoint.RunID != "" {
+			id = gm.checkpoint.RunID
+		} else {
+			gm.checkpoint.RunID = id
+			gm.checkpointDirty = true
+		}
+		gm.checkpointMu.Unlock()
+	}
+	
+	players := gm.config.Seats()
+	if players == nil {
+		players = game.DefaultSeats()
+	}
+	recorder, err := gm.database.StartRun(results.NewRun(id, gm.config, players))
+	if err != nil {
+		log.Printf("Warning: %v, not recording this run", err)
+		return
+	}
+	log.Printf("Recording run %s in %s", id, gm.config.DatabaseFile)
+	
+	if gm.exporter == nil {
+		gm.exporter = recorder
+	} else {
+		gm.exporter = exporters{gm.exporter, recorder}
+	}
+}
+
+// closeExporter writes the tournament summary, finished or stopped, and
+// closes the results file
+func (gm *GameManager) closeExporter() {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	
+	// A finished tournament already has its intervals; a stopped one gets
+	// them from the games it finished
+	if !gm.tournament.IsComplete() {
+		gm.tournament.ComputeSignificance()
+	}
+	if err := gm.exporter.WriteSummary(gm.tournament); err != nil {
+		log.Printf("Error writing results summary: %v", err)
+	}
+	if err := gm.exporter.Close(); err != nil {
+		log.Printf("Error closing results file: %v", err)
+	}
+}
+
 // Stop gracefully stops the tournament
 func (gm *GameManager) Stop() {
 	log.Println("Stopping tournament...")
 	gm.cancel()
 	
+	gm.mu.RLock()
+	if gm.multiTable != nil {
+		gm.multiTable.Stop()
+	}
+	for _, g := range gm.games {
+		g.Stop()
+	}
+	gm.mu.RUnlock()
+	
 	// Stop web servers
 	gm.stopWebServers()
-	
-	if gm.exporter != nil {
-		gm.exporter.Close()
-	}
 }
 
 // GetTournamentResult returns the current tournament result
@@ -0,0 +1,110 @@
+package tournament
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MikeLuu99/poker-arena/internal/ai/aitest"
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// runTournament plays games against calling stations, writing results to
+// output if it isn't empty
+func runTournament(t *testing.T, games int, seed int64, output string) *models.TournamentResult {
+	t.Helper()
+	srv := aitest.NewServer(aitest.Fixed("call", 0))
+	defer srv.Close()
+	defer srv.UseAsOpenRouter()()
+
+	config := models.DefaultConfig()
+	config.Games = games
+	config.NoServer = true
+	config.Seed = seed
+	config.ActionDelay = 0
+	config.OutputFile = output
+
+	result, err := NewGameManager(config).RunTournament()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestRunTournamentIsReproducible(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "test")
+
+	first := runTournament(t, 3, 21, "")
+	second := runTournament(t, 3, 21, "")
+
+	if !first.IsComplete() || first.CompletedGames != 3 {
+		t.Fatalf("completed %d of %d games", first.CompletedGames, first.TotalGames)
+	}
+	for _, stats := range first.PlayerStats {
+		if stats.TotalGames != 3 {
+			t.Errorf("%s played %d games, want 3", stats.Name, stats.TotalGames)
+		}
+	}
+
+	byID := func(tr *models.TournamentResult) map[int]*models.GameResult {
+		games := make(map[int]*models.GameResult)
+		for _, result := range tr.GameResults {
+			games[result.GameID] = result
+		}
+		return games
+	}
+	a, b := byID(first), byID(second)
+	for id, result := range a {
+		other, ok := b[id]
+		if !ok {
+			t.Fatalf("game %d missing from the second run", id)
+		}
+		if result.TotalHands != other.TotalHands {
+			t.Errorf("game %d: %d hands, then %d", id, result.TotalHands, other.TotalHands)
+		}
+		for i, ranking := range result.PlayerRankings {
+			if got := other.PlayerRankings[i]; got.Player.Name != ranking.Player.Name || got.Rank != ranking.Rank {
+				t.Errorf("game %d place %d: %s, then %s", id, i+1, ranking.Player.Name, got.Player.Name)
+			}
+		}
+	}
+	if first.OverallWinner != second.OverallWinner {
+		t.Errorf("overall winner %s, then %s", first.OverallWinner, second.OverallWinner)
+	}
+}
+
+func TestCSVSummaryGoesToItsOwnFile(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "test")
+	output := filepath.Join(t.TempDir(), "results.csv")
+	runTournament(t, 2, 5, output)
+
+	// Every row of the results has the header's columns
+	file, err := os.Open(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("results don't load as one table: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Errorf("results have %d rows, want a header and 2 games", len(rows))
+	}
+
+	summary, err := os.ReadFile(SummaryFilename(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := csv.NewReader(bytes.NewReader(summary))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) < 2 || records[0][0] != "TOURNAMENT SUMMARY" || records[1][2] != "2" {
+		t.Errorf("summary starts %v, want the summary header and 2 completed games", records[:min(2, len(records))])
+	}
+}
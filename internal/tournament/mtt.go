@@ -0,0 +1,364 @@
+package tournament
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/internal/game"
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// MultiTable runs one multi-table tournament. The field starts spread
+// evenly across tables, each a separate Game running on its own goroutine.
+// After every hand at a table the coordinator:
+//
+//   - seats players moved to the table from elsewhere,
+//   - records who busted, to build one finishing order for the whole field,
+//   - breaks the table up if the field fits on fewer tables, and
+//   - moves players to the shortest table if this one has two or more extra.
+//
+// Players move only from inside the callback of the table they leave, and
+// join at the callback of the table they go to, so no game is ever changed
+// while a hand is in progress. The last table standing is the final table,
+// and its winner wins the tournament.
+type MultiTable struct {
+	ID        int
+	TableSize int
+
+	mu      sync.Mutex
+	changed *sync.Cond // a table's players, or the tournament, changed
+	seats   []models.SeatConfig
+	tables  []*mttTable
+	byGame  map[*game.Game]*mttTable
+	stopped bool
+
+	remaining int            // players not yet busted
+	places    map[string]int // finishing place of busted players
+	busted    []string       // in order of elimination
+	final     *mttTable      // the final table, once it has formed
+	startTime time.Time
+}
+
+// mttTable is the coordinator's view of one table
+type mttTable struct {
+	number int
+	game   *game.Game
+	count  int              // players seated or on their way, not busted
+	inbox  []*game.Transfer // players moving here, seated at the next callback
+	closed bool
+}
+
+// NewMultiTable spreads the seats across as few tables of at most
+// tableSize as will hold them. No table starts with fewer than two players,
+// as it couldn't deal: an odd field at tables of two puts the extra player
+// at a table of three. newTable creates the game for each table.
+func NewMultiTable(id int, seats []models.SeatConfig, tableSize int, newTable func(number int, seats []models.SeatConfig) *game.Game) *MultiTable {
+	tableCount := (len(seats) + tableSize - 1) / tableSize
+	tableCount = max(1, min(tableCount, len(seats)/2))
+	tableSeats := make([][]models.SeatConfig, tableCount)
+	for i, seat := range seats {
+		tableSeats[i%tableCount] = append(tableSeats[i%tableCount], seat)
+	}
+
+	mt := &MultiTable{
+		ID:        id,
+		TableSize: tableSize,
+		seats:     seats,
+		byGame:    make(map[*game.Game]*mttTable),
+		remaining: len(seats),
+		places:    make(map[string]int),
+	}
+	mt.changed = sync.NewCond(&mt.mu)
+	for i, players := range tableSeats {
+		t := &mttTable{number: i + 1, game: newTable(i+1, players), count: len(players)}
+		t.game.SetBetweenHands(mt.betweenHands)
+		mt.tables = append(mt.tables, t)
+		mt.byGame[t.game] = t
+	}
+	return mt
+}
+
+// Games returns the tournament's tables
+func (mt *MultiTable) Games() []*game.Game {
+	games := make([]*game.Game, len(mt.tables))
+	for i, t := range mt.tables {
+		games[i] = t.game
+	}
+	return games
+}
+
+// Run plays the tournament to the end and returns its result, or nil if it
+// was stopped first
+func (mt *MultiTable) Run() *models.GameResult {
+	mt.startTime = time.Now()
+	log.Printf("🎪 Multi-table tournament %d: %d players at %d tables", mt.ID, len(mt.seats), len(mt.tables))
+
+	results := make([]*models.GameResult, len(mt.tables))
+	var wg sync.WaitGroup
+	for i, t := range mt.tables {
+		wg.Add(1)
+		go func(i int, t *mttTable) {
+			defer wg.Done()
+			results[i] = t.game.Start()
+
+			// A table whose game ended is done, however it ended. If it
+			// ended with players still seated or on their way, which
+			// happens when it had nobody left to play, they move on rather
+			// than drop out of the tournament.
+			mt.mu.Lock()
+			if !t.closed && !mt.stopped && t != mt.final {
+				mt.seatArrivals(t)
+				if open := mt.openTables(); len(open) > 1 {
+					for _, player := range t.game.ActivePlayers() {
+						mt.move(t, player.Name, mt.shortest(open, t))
+					}
+				}
+			}
+			t.closed = true
+			mt.changed.Broadcast()
+			mt.mu.Unlock()
+		}(i, t)
+	}
+	wg.Wait()
+
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+	if mt.stopped {
+		return nil
+	}
+	return mt.result(results)
+}
+
+// InterruptedResult scores a tournament stopped before it finished, once
+// Run has returned. Every table's hand in progress is called off, and the
+// players still in are ranked across tables and paid as
+// models.RankUnfinished describes.
+func (mt *MultiTable) InterruptedResult(payouts []float64) *models.GameResult {
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+
+	tables := make([]*models.GameResult, len(mt.tables))
+	dealt := false
+	for i, t := range mt.tables {
+		if tables[i] = t.game.InterruptedResult(nil); tables[i] == nil {
+			tables[i] = t.game.GetResult()
+		}
+		dealt = dealt || tables[i] != nil
+	}
+	if !dealt {
+		return nil
+	}
+
+	result := mt.result(tables)
+	result.Interrupted = true
+	result.PlayerRankings = models.RankUnfinished(result.AllPlayers, mt.places, payouts)
+	result.Winner = result.PlayerRankings[0].Player
+	result.FinalChips = result.Winner.Chips
+	return result
+}
+
+// Stop ends every table
+func (mt *MultiTable) Stop() {
+	mt.mu.Lock()
+	if mt.stopped {
+		mt.mu.Unlock()
+		return
+	}
+	mt.stopped = true
+	var running []*game.Game
+	for _, t := range mt.tables {
+		if !t.closed {
+			running = append(running, t.game)
+		}
+	}
+	mt.changed.Broadcast()
+	mt.mu.Unlock()
+
+	for _, g := range running {
+		g.Stop()
+	}
+}
+
+// betweenHands runs on a table's goroutine after each of its hands
+func (mt *MultiTable) betweenHands(g *game.Game) {
+	mt.mu.Lock()
+	defer mt.mu.Unlock()
+	defer mt.changed.Broadcast()
+
+	t := mt.byGame[g]
+	mt.seatArrivals(t)
+	mt.recordBusts(t, g.LastHand())
+
+	for {
+		if mt.stopped {
+			g.Close()
+			return
+		}
+
+		open := mt.openTables()
+		if len(open) == 1 {
+			if mt.final == nil {
+				mt.final = t
+				names := make([]string, 0, t.count)
+				for _, player := range g.ActivePlayers() {
+					names = append(names, player.Name)
+				}
+				log.Printf("🏁 Tournament %d final table (table %d): %s", mt.ID, t.number, strings.Join(names, ", "))
+			}
+			return
+		}
+
+		needed := (mt.remaining + mt.TableSize - 1) / mt.TableSize
+		if len(open) > needed && (t.count < 2 || mt.shortest(open, nil) == t) {
+			mt.breakTable(t)
+			return
+		}
+		if t.count >= 2 {
+			mt.balance(t, open)
+			return
+		}
+
+		// Too few players to deal and nowhere to send them: wait for
+		// another table to send someone, or for the field to shrink
+		// enough that this table should break
+		mt.changed.Wait()
+		mt.seatArrivals(t)
+	}
+}
+
+// seatArrivals seats the players moved to the table
+func (mt *MultiTable) seatArrivals(t *mttTable) {
+	for _, transfer := range t.inbox {
+		t.game.AddPlayer(transfer)
+	}
+	t.inbox = nil
+}
+
+// recordBusts gives the players busted by a hand their places in the whole
+// field
+func (mt *MultiTable) recordBusts(t *mttTable, hand models.HandRecord) {
+	for _, ranking := range models.PlaceBusts(hand, mt.remaining) {
+		name := ranking.Player.Name
+		mt.places[name] = ranking.Rank
+		mt.busted = append(mt.busted, name)
+		log.Printf("💥 Tournament %d: %s busts at table %d in %s place", mt.ID, name, t.number, models.Ordinal(ranking.Rank))
+	}
+	mt.remaining -= len(hand.Eliminated)
+	t.count -= len(hand.Eliminated)
+}
+
+// breakTable sends every player at the table to the shortest other tables
+// and closes it
+func (mt *MultiTable) breakTable(t *mttTable) {
+	players := t.game.ActivePlayers()
+	log.Printf("🪑 Tournament %d: breaking table %d (%d players)", mt.ID, t.number, len(players))
+	for _, player := range players {
+		mt.move(t, player.Name, mt.shortest(mt.openTables(), t))
+	}
+	t.closed = true
+	t.game.Close()
+}
+
+// balance moves players from the table while it has two or more more than
+// the shortest table
+func (mt *MultiTable) balance(t *mttTable, open []*mttTable) {
+	for {
+		shortest := mt.shortest(open, t)
+		if shortest == nil || t.count <= shortest.count+1 {
+			return
+		}
+		mt.move(t, t.game.NextBigBlind(), shortest)
+	}
+}
+
+// move takes a player off one table and queues them for another
+func (mt *MultiTable) move(from *mttTable, name string, to *mttTable) {
+	transfer, ok := from.game.RemovePlayer(name)
+	if !ok {
+		return
+	}
+	from.count--
+	to.inbox = append(to.inbox, transfer)
+	to.count++
+	log.Printf("🪑 Tournament %d: %s moves from table %d to table %d", mt.ID, name, from.number, to.number)
+}
+
+func (mt *MultiTable) openTables() []*mttTable {
+	var open []*mttTable
+	for _, t := range mt.tables {
+		if !t.closed {
+			open = append(open, t)
+		}
+	}
+	return open
+}
+
+// shortest returns the open table with fewest players other than except,
+// preferring the highest-numbered one so tables break from the end
+func (mt *MultiTable) shortest(open []*mttTable, except *mttTable) *mttTable {
+	var best *mttTable
+	for _, t := range open {
+		if t == except {
+			continue
+		}
+		if best == nil || t.count < best.count || (t.count == best.count && t.number > best.number) {
+			best = t
+		}
+	}
+	return best
+}
+
+// result combines the tables' results into one for the whole field
+func (mt *MultiTable) result(tables []*models.GameResult) *models.GameResult {
+	result := &models.GameResult{
+		GameID:      mt.ID,
+		Eliminated:  mt.busted,
+		StartTime:   mt.startTime,
+		EndTime:     time.Now(),
+		PlayerStats: make(map[string]*models.PlayerGameStats),
+		Seats:       mt.seats,
+	}
+	result.GameDuration = result.EndTime.Sub(result.StartTime).String()
+
+	finalChips := make(map[string]int)
+	for i, table := range tables {
+		if table == nil {
+			continue
+		}
+		result.StartingChips = table.StartingChips
+		result.FallbackUsed = result.FallbackUsed || table.FallbackUsed
+		for name, stats := range table.PlayerStats {
+			result.PlayerStats[name] = stats
+		}
+		for _, hand := range table.Hands {
+			hand.Table = mt.tables[i].number
+			result.Hands = append(result.Hands, hand)
+		}
+		// Only the final table's winner won the tournament
+		if table.Winner.Name != "" && mt.tables[i] == mt.final {
+			result.Winner = table.Winner
+			result.FinalChips = table.FinalChips
+			for _, player := range table.AllPlayers {
+				finalChips[player.Name] = player.Chips
+			}
+		}
+	}
+	result.TotalHands = len(result.Hands)
+
+	// Style statistics follow players across tables, so count them over
+	// every hand rather than per table
+	for name, counts := range models.CountStyle(result.Hands) {
+		if stats, ok := result.PlayerStats[name]; ok {
+			stats.Style = *counts
+		}
+	}
+
+	for _, seat := range mt.seats {
+		player := models.Player{Name: seat.Name, Model: seat.Model, Chips: finalChips[seat.Name], Cards: []string{}}
+		result.AllPlayers = append(result.AllPlayers, player)
+	}
+	result.PlayerRankings = models.RankStandings(result.AllPlayers, mt.places)
+	return result
+}
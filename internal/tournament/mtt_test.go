@@ -0,0 +1,155 @@
+package tournament
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/MikeLuu99/poker-arena/internal/ai/aitest"
+	"github.com/MikeLuu99/poker-arena/internal/game"
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// newTestMultiTable sets up a tournament of n players at tables of
+// tableSize, with seeded decks and no pause between actions
+func newTestMultiTable(n, tableSize int, seed int64) *MultiTable {
+	return NewMultiTable(1, testPool(n), tableSize, func(number int, seats []models.SeatConfig) *game.Game {
+		g := game.NewGameWithSeats(number, seats)
+		g.SetSeed(seed + int64(number))
+		g.SetActionDelay(0)
+		return g
+	})
+}
+
+// runMultiTable plays the tournament, failing if it doesn't finish in time
+func runMultiTable(t *testing.T, mt *MultiTable) *models.GameResult {
+	t.Helper()
+	done := make(chan *models.GameResult, 1)
+	go func() { done <- mt.Run() }()
+	select {
+	case result := <-done:
+		if result == nil {
+			t.Fatal("tournament returned no result")
+		}
+		return result
+	case <-time.After(30 * time.Second):
+		mt.Stop()
+		t.Fatal("tournament didn't finish; tables are waiting on each other")
+		return nil
+	}
+}
+
+func TestNewMultiTableSeatsEveryTableToDeal(t *testing.T) {
+	tests := []struct {
+		players, tableSize int
+		want               []int
+	}{
+		{9, 4, []int{3, 3, 3}},
+		{8, 4, []int{4, 4}},
+		{6, 3, []int{3, 3}},
+		{4, 2, []int{2, 2}},
+		{5, 2, []int{3, 2}},
+		{7, 2, []int{3, 2, 2}},
+		{3, 2, []int{3}},
+	}
+	for _, test := range tests {
+		mt := newTestMultiTable(test.players, test.tableSize, 1)
+		var got []int
+		for _, g := range mt.Games() {
+			got = append(got, len(g.ActivePlayers()))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(test.want) {
+			t.Errorf("%d players at tables of %d seated %v, want %v", test.players, test.tableSize, got, test.want)
+		}
+	}
+}
+
+func TestMultiTablePlaysToOneWinner(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "test")
+	srv := aitest.NewServer(aitest.RandomLegal(4))
+	defer srv.Close()
+	defer srv.UseAsOpenRouter()()
+
+	tests := []struct {
+		players, tableSize int
+	}{
+		{3, 2},
+		{5, 2},
+		{7, 2},
+		{9, 4},
+		{10, 3},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d at %d", test.players, test.tableSize), func(t *testing.T) {
+			mt := newTestMultiTable(test.players, test.tableSize, 7)
+			result := runMultiTable(t, mt)
+
+			if len(result.PlayerRankings) != test.players {
+				t.Fatalf("%d rankings, want %d", len(result.PlayerRankings), test.players)
+			}
+			winner := result.PlayerRankings[0]
+			if winner.Rank != 1 || winner.Player.Name != result.Winner.Name {
+				t.Errorf("first ranking %+v, but the winner is %s", winner, result.Winner.Name)
+			}
+			if total := test.players * 20; result.FinalChips != total || winner.Player.Chips != total {
+				t.Errorf("winner has %d chips, want all %d", result.FinalChips, total)
+			}
+
+			// Everyone else busted, each with a place after the winner
+			if len(result.Eliminated) != test.players-1 {
+				t.Errorf("%d players busted, want %d", len(result.Eliminated), test.players-1)
+			}
+			seen := make(map[string]bool)
+			for _, ranking := range result.PlayerRankings {
+				if seen[ranking.Player.Name] {
+					t.Errorf("%s ranked twice", ranking.Player.Name)
+				}
+				seen[ranking.Player.Name] = true
+				if ranking.Player.Name != winner.Player.Name && (ranking.Rank < 2 || ranking.Player.Chips != 0) {
+					t.Errorf("loser ranking %+v", ranking)
+				}
+			}
+
+			// Every player took part in some hand
+			played := make(map[string]bool)
+			for _, hand := range result.Hands {
+				for _, action := range hand.Actions {
+					played[action.Player] = true
+				}
+			}
+			if len(played) != test.players {
+				t.Errorf("%d of %d players were dealt in", len(played), test.players)
+			}
+		})
+	}
+}
+
+func TestMultiTableStop(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "test")
+	srv := aitest.NewServer(aitest.Slow(20*time.Millisecond, aitest.Fixed("call", 0)))
+	defer srv.Close()
+	defer srv.UseAsOpenRouter()()
+
+	mt := newTestMultiTable(8, 4, 3)
+	done := make(chan *models.GameResult, 1)
+	go func() { done <- mt.Run() }()
+	time.Sleep(300 * time.Millisecond)
+	mt.Stop()
+
+	select {
+	case result := <-done:
+		if result != nil {
+			t.Errorf("stopped tournament returned a result won by %s", result.Winner.Name)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("tables didn't stop")
+	}
+
+	interrupted := mt.InterruptedResult([]float64{70, 30})
+	if interrupted == nil || !interrupted.Interrupted {
+		t.Fatal("no interrupted result after hands were dealt")
+	}
+	if len(interrupted.PlayerRankings) != 8 {
+		t.Errorf("%d rankings, want 8", len(interrupted.PlayerRankings))
+	}
+}
@@ -0,0 +1,345 @@
+package tournament
+
+import (
+	"log"
+	"math"
+	"math/big"
+	"math/rand"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+// Scheduling methods for a player pool larger than one table
+const (
+	ScheduleBalanced   = "balanced"
+	ScheduleRoundRobin = "round-robin"
+)
+
+// ScheduleTables picks the seats for each game from the definition's
+// player pool, or returns nil when every player sits at the one table or
+// games are multi-table tournaments
+func ScheduleTables(config *models.Config) [][]models.SeatConfig {
+	def := config.Definition
+	if def == nil || def.IsMultiTable() || def.TableSize <= 0 || def.TableSize >= len(def.Players) {
+		return nil
+	}
+
+	method := def.Schedule
+	if method == "" {
+		method = ScheduleBalanced
+	}
+	if method == ScheduleRoundRobin {
+		cycle := roundRobinCycle(len(def.Players), def.TableSize)
+		if new(big.Int).Mod(big.NewInt(int64(config.Games)), cycle).Sign() != 0 {
+			log.Printf("Warning: a round-robin cycle is %s games, so %d games leave some pairs meeting more often than others. Use a multiple of %s games, or the balanced schedule", cycle, config.Games, cycle)
+		}
+	}
+	if config.Verbose {
+		log.Printf("Scheduling %d players into %d tables of %d (%s)", len(def.Players), config.Games, def.TableSize, method)
+	}
+	return Schedule(def.Players, def.TableSize, config.Games, method)
+}
+
+// Schedule picks the seats for each of games tables of tableSize players
+// drawn from the pool.
+//
+// ScheduleRoundRobin cycles through every possible table in turn, so after
+// each full cycle every pair has met equally often. ScheduleBalanced builds
+// each table greedily from the players with the fewest games and the pairs
+// that have met least, then swaps players between tables to even out the
+// pairings over all the games. It finds a balanced incomplete block design
+// when one exists for the sizes, and gets close to one when none does.
+//
+// Within a table, seats rotate: each position goes to the player who has
+// sat there least, so nobody is stuck on the button or in the blinds.
+func Schedule(pool []models.SeatConfig, tableSize, games int, method string) [][]models.SeatConfig {
+	if tableSize <= 0 || tableSize > len(pool) {
+		tableSize = len(pool)
+	}
+
+	var tables [][]int
+	if method == ScheduleRoundRobin {
+		tables = roundRobinTables(len(pool), tableSize, games)
+	} else {
+		tables = balancedTables(len(pool), tableSize, games)
+	}
+
+	positions := make([][]int, len(pool)) // [player][position] = games seated there
+	for i := range positions {
+		positions[i] = make([]int, tableSize)
+	}
+
+	schedule := make([][]models.SeatConfig, len(tables))
+	for g, table := range tables {
+		seats := make([]models.SeatConfig, tableSize)
+		taken := make(map[int]bool)
+		for position := range seats {
+			best := -1
+			for _, player := range table {
+				if taken[player] {
+					continue
+				}
+				if best == -1 || positions[player][position] < positions[best][position] {
+					best = player
+				}
+			}
+			taken[best] = true
+			positions[best][position]++
+			seats[position] = pool[best]
+		}
+		schedule[g] = seats
+	}
+	return schedule
+}
+
+// roundRobinTables takes games tables from the cycle of every
+// tableSize-subset of n players in lexicographic order, repeating the cycle
+// until there are enough games. Consecutive subsets share most of their
+// players, so the cycle is visited with a stride coprime to its length to
+// spread players out. Each table is built from its index in the cycle, as
+// the cycle of a large pool is far too long to list.
+func roundRobinTables(n, tableSize, games int) [][]int {
+	cycle := roundRobinCycle(n, tableSize)
+	stride := coprimeStride(cycle)
+
+	tables := make([][]int, games)
+	index := new(big.Int)
+	for g := range tables {
+		index.Mul(big.NewInt(int64(g)), stride)
+		tables[g] = combination(n, tableSize, index.Mod(index, cycle))
+	}
+	return tables
+}
+
+// roundRobinCycle returns the number of games in a full round-robin cycle:
+// every tableSize-subset of n players
+func roundRobinCycle(n, tableSize int) *big.Int {
+	return new(big.Int).Binomial(int64(n), int64(tableSize))
+}
+
+// combination returns the tableSize-subset of n players at index in
+// lexicographic order
+func combination(n, tableSize int, index *big.Int) []int {
+	rank := new(big.Int).Set(index)
+	count := new(big.Int)
+	table := make([]int, 0, tableSize)
+	for player := 0; len(table) < tableSize; player++ {
+		// Subsets that take player next
+		count.Binomial(int64(n-player-1), int64(tableSize-len(table)-1))
+		if rank.Cmp(count) < 0 {
+			table = append(table, player)
+		} else {
+			rank.Sub(rank, count)
+		}
+	}
+	return table
+}
+
+// coprimeStride returns a step near the golden ratio of n that visits
+// every index mod n exactly once. The ratio is taken at float64 precision.
+func coprimeStride(n *big.Int) *big.Int {
+	one := big.NewInt(1)
+	ratio := new(big.Float).SetPrec(53).SetInt(n)
+	ratio.Mul(ratio, big.NewFloat(0.618))
+	stride, _ := ratio.Int(nil)
+	stride.Add(stride, one)
+
+	gcd := new(big.Int)
+	for ; stride.Cmp(one) > 0; stride.Sub(stride, one) {
+		if gcd.GCD(nil, nil, stride, n).Cmp(one) == 0 {
+			return stride
+		}
+	}
+	return one
+}
+
+// balancedTables seats each player within one game of every other, then
+// swaps players between tables to even out how often each pair meets
+func balancedTables(n, tableSize, games int) [][]int {
+	tables := greedyTables(n, tableSize, games)
+	evenPairs(n, tables)
+	return tables
+}
+
+// greedyTables builds each table one player at a time, first whoever has
+// played fewest games, then whoever has met the players already seated
+// least. It then swaps players in and out while that lowers the table's
+// cost: fewer games already played, then fewer repeat meetings.
+func greedyTables(n, tableSize, games int) [][]int {
+	played := make([]int, n)
+	met := make([][]int, n)
+	for i := range met {
+		met[i] = make([]int, n)
+	}
+
+	// cost of a table, compared games first
+	cost := func(table []int) (int, int) {
+		games, meetings := 0, 0
+		for i, a := range table {
+			games += played[a]
+			for _, b := range table[i+1:] {
+				meetings += met[a][b]
+			}
+		}
+		return games, meetings
+	}
+	less := func(g1, m1, g2, m2 int) bool {
+		return g1 < g2 || (g1 == g2 && m1 < m2)
+	}
+
+	tables := make([][]int, games)
+	for g := range tables {
+		var table []int
+		seated := make([]bool, n)
+		for len(table) < tableSize {
+			best, bestMet := -1, 0
+			for player := 0; player < n; player++ {
+				if seated[player] {
+					continue
+				}
+				meetings := 0
+				for _, other := range table {
+					meetings += met[player][other]
+				}
+				if best == -1 || played[player] < played[best] ||
+					(played[player] == played[best] && meetings < bestMet) {
+					best, bestMet = player, meetings
+				}
+			}
+			seated[best] = true
+			table = append(table, best)
+		}
+
+		for improved := true; improved; {
+			improved = false
+			tableGames, tableMet := cost(table)
+			for i := range table {
+				for player := 0; player < n && !improved; player++ {
+					if seated[player] {
+						continue
+					}
+					previous := table[i]
+					table[i] = player
+					if g, m := cost(table); less(g, m, tableGames, tableMet) {
+						seated[previous], seated[player] = false, true
+						improved = true
+						break
+					}
+					table[i] = previous
+				}
+			}
+		}
+
+		for _, a := range table {
+			played[a]++
+			for _, b := range table {
+				if a != b {
+					met[a][b]++
+				}
+			}
+		}
+		tables[g] = table
+	}
+	return tables
+}
+
+// evenPairs swaps players between tables to spread meetings evenly over
+// every pair, by simulated annealing on the sum of squared meeting counts.
+// A swap only exchanges two players between two tables, so everyone keeps
+// the same number of games. It stops early once no schedule could be more
+// even, which a block design reaches when one exists for these sizes.
+func evenPairs(n int, tables [][]int) {
+	if len(tables) < 2 || len(tables[0]) < 2 || len(tables[0]) == n {
+		return
+	}
+
+	met := make([][]int, n)
+	for i := range met {
+		met[i] = make([]int, n)
+	}
+	cost := 0
+	// meet changes how often a and b have met by change, returning the
+	// change in cost
+	meet := func(a, b, change int) int {
+		before := met[a][b]
+		met[a][b] += change
+		met[b][a] += change
+		return met[a][b]*met[a][b] - before*before
+	}
+	for _, table := range tables {
+		for i, a := range table {
+			for _, b := range table[i+1:] {
+				cost += meet(a, b, 1)
+			}
+		}
+	}
+
+	// The most even schedule spreads the meetings over every pair with
+	// counts at most one apart
+	size := len(tables[0])
+	meetings, pairs := len(tables)*size*(size-1)/2, n*(n-1)/2
+	each, extra := meetings/pairs, meetings%pairs
+	lowest := (pairs-extra)*each*each + extra*(each+1)*(each+1)
+
+	best := cost
+	bestTables := cloneTables(tables)
+	seated := func(table []int, player int) bool {
+		for _, other := range table {
+			if other == player {
+				return true
+			}
+		}
+		return false
+	}
+	// swap exchanges the players at i of table x and j of table y,
+	// returning the change in cost
+	swap := func(x, i, y, j int) int {
+		a, b := tables[x][i], tables[y][j]
+		change := 0
+		for k, other := range tables[x] {
+			if k != i {
+				change += meet(a, other, -1) + meet(b, other, 1)
+			}
+		}
+		for k, other := range tables[y] {
+			if k != j {
+				change += meet(b, other, -1) + meet(a, other, 1)
+			}
+		}
+		tables[x][i], tables[y][j] = b, a
+		return change
+	}
+
+	// A fixed seed keeps the schedule the same from run to run
+	rng := rand.New(rand.NewSource(1))
+	steps := min(2000*len(tables)*size, 2_000_000)
+	temperature, cooling := 2.0, math.Pow(0.01/2.0, 1/float64(steps))
+	for step := 0; step < steps && best > lowest; step++ {
+		temperature *= cooling
+		x, y := rng.Intn(len(tables)), rng.Intn(len(tables))
+		i, j := rng.Intn(size), rng.Intn(size)
+		if x == y || seated(tables[y], tables[x][i]) || seated(tables[x], tables[y][j]) {
+			continue
+		}
+
+		change := swap(x, i, y, j)
+		if change > 0 && rng.Float64() >= math.Exp(-float64(change)/temperature) {
+			swap(x, i, y, j)
+			continue
+		}
+		cost += change
+		if cost < best {
+			best = cost
+			bestTables = cloneTables(tables)
+		}
+	}
+	copy(tables, bestTables)
+}
+
+func cloneTables(tables [][]int) [][]int {
+	clone := make([][]int, len(tables))
+	for i, table := range tables {
+		clone[i] = append([]int(nil), table...)
+	}
+	return clone
+}
@@ -0,0 +1,191 @@
+package tournament
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/MikeLuu99/poker-arena/pkg/models"
+)
+
+func testPool(n int) []models.SeatConfig {
+	pool := make([]models.SeatConfig, n)
+	for i := range pool {
+		pool[i] = models.SeatConfig{Name: fmt.Sprintf("p%d", i)}
+	}
+	return pool
+}
+
+func TestRoundRobinCycle(t *testing.T) {
+	tests := []struct {
+		players, tableSize, cycle int
+	}{
+		{5, 3, 10},
+		{6, 4, 15},
+		{7, 4, 35},
+		{8, 2, 28},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d at %d", test.players, test.tableSize), func(t *testing.T) {
+			schedule := Schedule(testPool(test.players), test.tableSize, 2*test.cycle, ScheduleRoundRobin)
+
+			tables := make(map[string]int)
+			met := make(map[[2]string]int)
+			seats := make(map[string]map[int]bool)
+			for g, table := range schedule {
+				if len(table) != test.tableSize {
+					t.Fatalf("game %d seats %d players, want %d", g, len(table), test.tableSize)
+				}
+				var names []string
+				for position, seat := range table {
+					names = append(names, seat.Name)
+					if g < test.cycle {
+						if seats[seat.Name] == nil {
+							seats[seat.Name] = make(map[int]bool)
+						}
+						seats[seat.Name][position] = true
+					}
+				}
+				sort.Strings(names)
+				key := strings.Join(names, ",")
+				if g < test.cycle {
+					tables[key]++
+					for i, a := range names {
+						for _, b := range names[i+1:] {
+							met[[2]string{a, b}]++
+						}
+					}
+				} else if first := schedule[g-test.cycle]; !sameTable(first, table) {
+					t.Errorf("game %d is not a repeat of game %d", g, g-test.cycle)
+				}
+			}
+
+			// One full cycle: every possible table once, so every pair meets
+			// the same number of times
+			if len(tables) != test.cycle {
+				t.Errorf("cycle has %d distinct tables, want %d", len(tables), test.cycle)
+			}
+			pairs := test.players * (test.players - 1) / 2
+			if len(met) != pairs {
+				t.Errorf("%d pairs met, want all %d", len(met), pairs)
+			}
+			for pair, n := range met {
+				if want := test.cycle * test.tableSize * (test.tableSize - 1) / (test.players * (test.players - 1)); n != want {
+					t.Errorf("%s and %s met %d times, want %d", pair[0], pair[1], n, want)
+				}
+			}
+
+			// Seats rotate: over the cycle everyone sits in every position
+			for name, positions := range seats {
+				if len(positions) != test.tableSize {
+					t.Errorf("%s sat in %d of the %d positions", name, len(positions), test.tableSize)
+				}
+			}
+		})
+	}
+}
+
+func sameTable(a, b []models.SeatConfig) bool {
+	names := func(table []models.SeatConfig) string {
+		var names []string
+		for _, seat := range table {
+			names = append(names, seat.Name)
+		}
+		sort.Strings(names)
+		return strings.Join(names, ",")
+	}
+	return names(a) == names(b)
+}
+
+func TestRoundRobinLargePool(t *testing.T) {
+	// The cycle is C(40, 8), about 77 million tables, of which only a few
+	// are played
+	schedule := Schedule(testPool(40), 8, 10, ScheduleRoundRobin)
+	if len(schedule) != 10 {
+		t.Fatalf("%d tables, want 10", len(schedule))
+	}
+	seen := make(map[string]bool)
+	for g, table := range schedule {
+		seated := make(map[string]bool)
+		for _, seat := range table {
+			if seated[seat.Name] {
+				t.Errorf("game %d seats %s twice", g, seat.Name)
+			}
+			seated[seat.Name] = true
+		}
+		if key := fmt.Sprint(table); seen[key] {
+			t.Errorf("game %d repeats a table", g)
+		} else {
+			seen[key] = true
+		}
+	}
+}
+
+func TestBalancedTables(t *testing.T) {
+	tests := []struct {
+		players, tableSize, games int
+		spread                    int // most the pair counts may differ by
+	}{
+		// Block designs exist for these, so every pair meets equally often
+		{13, 4, 13, 0},
+		{7, 3, 7, 0},
+		{9, 3, 12, 0},
+		{16, 4, 20, 0},
+		{8, 4, 14, 0},
+		// Not every pair can meet equally often
+		{10, 4, 7, 2},
+		{12, 4, 30, 1},
+		{20, 5, 40, 2},
+		{40, 8, 10, 1},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d at %d for %d", test.players, test.tableSize, test.games), func(t *testing.T) {
+			tables := balancedTables(test.players, test.tableSize, test.games)
+			if len(tables) != test.games {
+				t.Fatalf("%d tables, want %d", len(tables), test.games)
+			}
+
+			played := make([]int, test.players)
+			met := make(map[[2]int]int)
+			for g, table := range tables {
+				seated := make(map[int]bool)
+				for _, player := range table {
+					if seated[player] {
+						t.Fatalf("game %d seats player %d twice", g, player)
+					}
+					seated[player] = true
+					played[player]++
+				}
+				if len(seated) != test.tableSize {
+					t.Fatalf("game %d seats %d players, want %d", g, len(seated), test.tableSize)
+				}
+				for a := range seated {
+					for b := range seated {
+						if a < b {
+							met[[2]int{a, b}]++
+						}
+					}
+				}
+			}
+
+			fewest, most := played[0], played[0]
+			for _, n := range played {
+				fewest, most = min(fewest, n), max(most, n)
+			}
+			if most-fewest > 1 || (test.games*test.tableSize%test.players == 0 && most != fewest) {
+				t.Errorf("players have between %d and %d games", fewest, most)
+			}
+
+			fewest, most = test.games, 0
+			for a := 0; a < test.players; a++ {
+				for b := a + 1; b < test.players; b++ {
+					fewest, most = min(fewest, met[[2]int{a, b}]), max(most, met[[2]int{a, b}])
+				}
+			}
+			if most-fewest > test.spread {
+				t.Errorf("pairs met between %d and %d times, want a spread of at most %d", fewest, most, test.spread)
+			}
+		})
+	}
+}
@@ -1,13 +1,19 @@
 package models
 
+import "time"
+
 // Config holds the application configuration
 type Config struct {
 	// Number of parallel games to run
 	Games int
 	
-	// CSV output file path
+	// Results file path
 	OutputFile string
 	
+	// Results file format: csv, json or ndjson. Empty picks it from the
+	// file's extension.
+	OutputFormat string
+	
 	// Whether to disable the web server (batch mode)
 	NoServer bool
 	
@@ -22,6 +28,40 @@ type Config struct {
 	
 	// Show help
 	Help bool
+
+	// Path to a JSON tournament definition (players, endpoints, ...)
+	TournamentFile string
+
+	// Loaded tournament definition, nil when using the built-in lineup
+	Definition *TournamentDefinition
+
+	// Deck seed; game N uses Seed+N-1. 0 deals random decks.
+	Seed int64
+
+	// Pause between actions
+	ActionDelay time.Duration
+
+	// Time a player has for each decision (0 for no limit), and extra time
+	// per game that decisions can draw on once the limit is used up
+	ActionTimeout time.Duration
+	TimeBank      time.Duration
+
+	// JSON file of player ratings, updated after every game
+	RatingsFile string
+
+	// Record all model requests/responses to this cassette file
+	RecordFile string
+
+	// Replay model responses from this cassette file instead of calling the API
+	ReplayFile string
+
+	// Results database collecting every run's games, hands and actions
+	DatabaseFile string
+
+	// Save tournament progress to this file as games play, and with Resume
+	// carry on from the progress saved there
+	CheckpointFile string
+	Resume         bool
 }
 
 // DefaultConfig returns the default configuration
@@ -34,12 +74,30 @@ func DefaultConfig() *Config {
 		Verbose:     false,
 		Port:        "3000",
 		Help:        false,
+		ActionDelay:   2 * time.Second,
+		ActionTimeout: 30 * time.Second,
+	}
+}
+
+// GameSeed returns the deck seed for a game, or 0 for a random deck
+func (c *Config) GameSeed(gameID int) int64 {
+	if c.Seed == 0 {
+		return 0
+	}
+	return c.Seed + int64(gameID-1)
+}
+
+// Seats returns the configured seats, or nil to use the built-in lineup
+func (c *Config) Seats() []SeatConfig {
+	if c.Definition == nil {
+		return nil
 	}
+	return c.Definition.Players
 }
 
 // IsBatchMode returns true if running in batch mode (no web server)
 func (c *Config) IsBatchMode() bool {
-	return c.NoServer || (c.Games > 1 && !c.WithServers)
+	return c.NoServer || (c.Games > 1 && !c.WithServers) || c.Definition.IsMultiTable()
 }
 
 // IsParallel returns true if running multiple games in parallel
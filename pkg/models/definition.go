@@ -0,0 +1,434 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SeatConfig describes the agent sitting in one seat of a game
+type SeatConfig struct {
+	// Display name, used as the player's identity in results (defaults to Model)
+	Name string `json:"name"`
+
+	// Model identifier sent to the chat completions endpoint
+	Model string `json:"model"`
+
+	// OpenAI-compatible base URL, e.g. http://localhost:11434/v1 for Ollama.
+	// Empty means OpenRouter.
+	BaseURL string `json:"baseUrl,omitempty"`
+
+	// Environment variable holding the API key for this seat. Seats with a
+	// custom BaseURL and no key variable send no Authorization header.
+	APIKeyEnv string `json:"apiKeyEnv,omitempty"`
+
+	// Ask for a JSON action in the message content instead of a tool call,
+	// for servers that don't support tool calling
+	NoTools bool `json:"noTools,omitempty"`
+
+	// Path to a text/template prompt file. Empty uses the built-in prompt.
+	PromptTemplate string `json:"promptTemplate,omitempty"`
+
+	// Carry a rolling summary of recent hands and opponents' tendencies
+	// into each prompt
+	Memory bool `json:"memory,omitempty"`
+
+	// Number of recent hands to summarize (default 10)
+	MemoryHands int `json:"memoryHands,omitempty"`
+
+	// Let the model save notes that are shown to it in later hands
+	NoteTaking bool `json:"noteTaking,omitempty"`
+
+	// Attempts the model gets to produce a legal action before the seat
+	// checks or folds (default 3)
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+
+	// Token prices used to estimate cost. Filled from the definition's
+	// price table when not set on the seat.
+	Price *ModelPrice `json:"price,omitempty"`
+
+	// Generation parameters forwarded with every request. Unset values
+	// leave the provider's defaults in place.
+	Temperature     *float64             `json:"temperature,omitempty"`
+	TopP            *float64             `json:"topP,omitempty"`
+	MaxTokens       int                  `json:"maxTokens,omitempty"`
+	ReasoningEffort string               `json:"reasoningEffort,omitempty"` // minimal, low, medium or high
+	Seed            *int64               `json:"seed,omitempty"`
+	Provider        *ProviderPreferences `json:"provider,omitempty"` // OpenRouter provider routing
+
+	// Who decides for the seat when its model fails or its circuit is open.
+	// Without one, an open circuit checks or folds.
+	Fallback *FallbackConfig `json:"fallback,omitempty"`
+
+	// Time limits overriding the command line's --action-timeout and
+	// --time-bank for this seat
+	ActionTimeoutSeconds float64 `json:"actionTimeoutSeconds,omitempty"`
+	TimeBankSeconds      float64 `json:"timeBankSeconds,omitempty"`
+
+	// When to stop calling a failing model (default: after 3 consecutive
+	// failures, probing again after 30 seconds)
+	CircuitBreaker *CircuitBreakerConfig `json:"circuitBreaker,omitempty"`
+}
+
+// Baseline bots that can stand in for a failing model
+var BaselineBots = []string{"check-fold", "check-call", "random"}
+
+// FallbackConfig is either another model, configured like a seat, or one
+// of the BaselineBots
+type FallbackConfig struct {
+	SeatConfig
+	Bot string `json:"bot,omitempty"`
+}
+
+// CircuitBreakerConfig controls when a seat stops calling its model
+type CircuitBreakerConfig struct {
+	Failures        int     `json:"failures,omitempty"`        // consecutive failures that open the circuit (default 3)
+	CooldownSeconds float64 `json:"cooldownSeconds,omitempty"` // wait before probing the model again (default 30)
+	Disabled        bool    `json:"disabled,omitempty"`
+}
+
+// IsLocal returns true if the seat talks to a custom (non-OpenRouter) server
+func (s SeatConfig) IsLocal() bool {
+	return s.BaseURL != ""
+}
+
+// ProviderPreferences is OpenRouter's provider routing object, forwarded
+// as is (https://openrouter.ai/docs/features/provider-routing)
+type ProviderPreferences struct {
+	Order             []string `json:"order,omitempty"`
+	Only              []string `json:"only,omitempty"`
+	Ignore            []string `json:"ignore,omitempty"`
+	AllowFallbacks    *bool    `json:"allow_fallbacks,omitempty"`
+	RequireParameters *bool    `json:"require_parameters,omitempty"`
+	DataCollection    string   `json:"data_collection,omitempty"` // allow or deny
+	Sort              string   `json:"sort,omitempty"`            // price, throughput or latency
+}
+
+// GenerationParams describes the seat's non-default generation parameters
+// in one line, e.g. "temperature=0.2 max_tokens=512"
+func (s SeatConfig) GenerationParams() string {
+	var params []string
+	if s.Temperature != nil {
+		params = append(params, "temperature="+strconv.FormatFloat(*s.Temperature, 'g', -1, 64))
+	}
+	if s.TopP != nil {
+		params = append(params, "top_p="+strconv.FormatFloat(*s.TopP, 'g', -1, 64))
+	}
+	if s.MaxTokens > 0 {
+		params = append(params, fmt.Sprintf("max_tokens=%d", s.MaxTokens))
+	}
+	if s.ReasoningEffort != "" {
+		params = append(params, "reasoning_effort="+s.ReasoningEffort)
+	}
+	if s.Seed != nil {
+		params = append(params, fmt.Sprintf("seed=%d", *s.Seed))
+	}
+	if p := s.Provider; p != nil {
+		var routing []string
+		if len(p.Order) > 0 {
+			routing = append(routing, "order:"+strings.Join(p.Order, ","))
+		}
+		if len(p.Only) > 0 {
+			routing = append(routing, "only:"+strings.Join(p.Only, ","))
+		}
+		if len(p.Ignore) > 0 {
+			routing = append(routing, "ignore:"+strings.Join(p.Ignore, ","))
+		}
+		if p.AllowFallbacks != nil && !*p.AllowFallbacks {
+			routing = append(routing, "no-fallbacks")
+		}
+		if p.Sort != "" {
+			routing = append(routing, "sort:"+p.Sort)
+		}
+		if len(routing) > 0 {
+			params = append(params, "provider="+strings.Join(routing, ";"))
+		}
+	}
+	return strings.Join(params, " ")
+}
+
+// validateFallback checks the fallback names exactly one agent
+func (s SeatConfig) validateFallback() error {
+	fallback := s.Fallback
+	if fallback == nil {
+		return nil
+	}
+	if (fallback.Model == "") == (fallback.Bot == "") {
+		return fmt.Errorf("player %q: fallback needs either a model or a bot", s.Name)
+	}
+	if fallback.Bot != "" {
+		for _, bot := range BaselineBots {
+			if fallback.Bot == bot {
+				return nil
+			}
+		}
+		return fmt.Errorf("player %q: unknown fallback bot %q (want one of %s)", s.Name, fallback.Bot, strings.Join(BaselineBots, ", "))
+	}
+	if fallback.Fallback != nil {
+		return fmt.Errorf("player %q: a fallback cannot have its own fallback", s.Name)
+	}
+	return fallback.validateGeneration()
+}
+
+// validateGeneration checks the generation parameters are in range
+func (s SeatConfig) validateGeneration() error {
+	if s.Temperature != nil && (*s.Temperature < 0 || *s.Temperature > 2) {
+		return fmt.Errorf("player %q: temperature must be between 0 and 2", s.Name)
+	}
+	if s.TopP != nil && (*s.TopP <= 0 || *s.TopP > 1) {
+		return fmt.Errorf("player %q: topP must be greater than 0 and at most 1", s.Name)
+	}
+	if s.ActionTimeoutSeconds < 0 || s.TimeBankSeconds < 0 {
+		return fmt.Errorf("player %q: time limits cannot be negative", s.Name)
+	}
+	if s.MaxTokens < 0 {
+		return fmt.Errorf("player %q: maxTokens cannot be negative", s.Name)
+	}
+	switch s.ReasoningEffort {
+	case "", "minimal", "low", "medium", "high":
+	default:
+		return fmt.Errorf("player %q: reasoningEffort must be minimal, low, medium or high", s.Name)
+	}
+	return nil
+}
+
+// ModelPrice is a model's price in dollars per million tokens
+type ModelPrice struct {
+	PromptPerMillion     float64 `json:"promptPerMillion"`
+	CompletionPerMillion float64 `json:"completionPerMillion"`
+}
+
+// Cost returns the dollar cost of the given token counts
+func (p ModelPrice) Cost(promptTokens, completionTokens int) float64 {
+	return (float64(promptTokens)*p.PromptPerMillion + float64(completionTokens)*p.CompletionPerMillion) / 1e6
+}
+
+// RateLimit is the request budget for one provider
+type RateLimit struct {
+	RequestsPerMinute float64 `json:"requestsPerMinute"`
+	Burst             int     `json:"burst,omitempty"` // requests allowed back to back (default 1)
+}
+
+// PromptExperiment seats the same model once per prompt variant, so the
+// variants play against each other as separate players
+type PromptExperiment struct {
+	SeatConfig
+
+	// Variant label -> prompt template path. Each variant becomes a seat
+	// named "<name> [<label>]".
+	Variants map[string]string `json:"variants"`
+}
+
+// TournamentDefinition is the JSON file describing who plays and how
+type TournamentDefinition struct {
+	Players           []SeatConfig       `json:"players"`
+	PromptExperiments []PromptExperiment `json:"promptExperiments,omitempty"`
+
+	// Request budgets by provider: the vendor prefix of an OpenRouter model
+	// ("openai", "anthropic", ...) or the host of a custom base URL
+	RateLimits map[string]RateLimit `json:"rateLimits,omitempty"`
+
+	// Token prices by model, used to estimate each seat's cost
+	Prices map[string]ModelPrice `json:"prices,omitempty"`
+
+	// Seats per game when Players is a pool larger than one table. Zero
+	// seats everyone in every game.
+	TableSize int `json:"tableSize,omitempty"`
+
+	// How tables are drawn from the pool: "balanced" (default) or
+	// "round-robin"
+	Schedule string `json:"schedule,omitempty"`
+
+	// "mtt" plays each game as a multi-table tournament: every player
+	// starts, spread across tables of at most TableSize, and tables are
+	// balanced and broken as players bust until one final table remains.
+	// "cash" plays cash games set up by Cash. Empty plays single-table
+	// freeze-outs.
+	Format string `json:"format,omitempty"`
+
+	// Cash game settings, for format "cash"
+	Cash *CashConfig `json:"cash,omitempty"`
+
+	// Prize for each finishing place, first place first, in any units.
+	// Freeze-outs and multi-table tournaments only.
+	Payouts []float64 `json:"payouts,omitempty"`
+}
+
+// CashConfig sets up cash games: a fixed length instead of playing to one
+// winner, with optional automatic top-ups
+type CashConfig struct {
+	Hands           int     `json:"hands,omitempty"`           // hands to play (default 100 if no duration is set)
+	DurationSeconds float64 `json:"durationSeconds,omitempty"` // wall-clock length; the game ends at whichever limit comes first
+	BuyIn           int     `json:"buyIn,omitempty"`           // starting stack (default 100 big blinds)
+	Rebuy           bool    `json:"rebuy,omitempty"`           // top stacks up automatically between hands
+	TopUpBelow      int     `json:"topUpBelow,omitempty"`      // top up stacks below this (default: only busted stacks)
+	TopUpTo         int     `json:"topUpTo,omitempty"`         // stack to top up to (default BuyIn)
+}
+
+// Every game is played at these blinds
+const (
+	SmallBlind = 5
+	BigBlind   = 10
+)
+
+// MinCashStack is the shallowest cash game buy-in or top-up, in big blinds.
+// Shorter stacks leave no room to play after the flop.
+const MinCashStack = 10
+
+// CashGame returns the cash game settings, or nil if games are not cash
+// games
+func (d *TournamentDefinition) CashGame() *CashConfig {
+	if d == nil || d.Format != "cash" {
+		return nil
+	}
+	return d.Cash
+}
+
+// IsMultiTable returns true if games are multi-table tournaments
+func (d *TournamentDefinition) IsMultiTable() bool {
+	return d != nil && d.Format == "mtt"
+}
+
+// LoadTournamentDefinition reads and validates a tournament definition file
+func LoadTournamentDefinition(path string) (*TournamentDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tournament definition: %w", err)
+	}
+
+	var def TournamentDefinition
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("failed to parse tournament definition: %w", err)
+	}
+
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+	return &def, nil
+}
+
+// Validate fills in defaults, expands prompt experiments into seats and
+// checks the seats are usable
+func (d *TournamentDefinition) Validate() error {
+	for _, exp := range d.PromptExperiments {
+		seats, err := exp.Seats()
+		if err != nil {
+			return err
+		}
+		d.Players = append(d.Players, seats...)
+	}
+	d.PromptExperiments = nil
+
+	if len(d.Players) < 2 {
+		return fmt.Errorf("tournament definition needs at least 2 players, got %d", len(d.Players))
+	}
+	if d.TableSize != 0 && (d.TableSize < 2 || d.TableSize > len(d.Players)) {
+		return fmt.Errorf("tableSize must be between 2 and the number of players (%d), got %d", len(d.Players), d.TableSize)
+	}
+	switch d.Format {
+	case "":
+	case "mtt":
+		if d.TableSize == 0 || d.TableSize == len(d.Players) {
+			return fmt.Errorf("format mtt needs a tableSize smaller than the number of players (%d)", len(d.Players))
+		}
+	case "cash":
+		if d.Cash == nil {
+			d.Cash = &CashConfig{}
+		}
+		cash := d.Cash
+		if cash.Hands < 0 || cash.DurationSeconds < 0 || cash.BuyIn < 0 || cash.TopUpBelow < 0 || cash.TopUpTo < 0 {
+			return fmt.Errorf("cash game settings must not be negative")
+		}
+		if minimum := MinCashStack * BigBlind; (cash.BuyIn > 0 && cash.BuyIn < minimum) || (cash.TopUpTo > 0 && cash.TopUpTo < minimum) {
+			return fmt.Errorf("cash game buyIn and topUpTo must be at least %d big blinds (%d chips)", MinCashStack, minimum)
+		}
+		if cash.Hands == 0 && cash.DurationSeconds == 0 {
+			cash.Hands = 100
+		}
+	default:
+		return fmt.Errorf("unknown format %q (use mtt or cash, or leave it empty for freeze-outs)", d.Format)
+	}
+	if len(d.Payouts) > 0 {
+		if d.Format == "cash" {
+			return fmt.Errorf("payouts don't apply to cash games")
+		}
+		if len(d.Payouts) > len(d.Players) {
+			return fmt.Errorf("payouts has %d places but there are only %d players", len(d.Payouts), len(d.Players))
+		}
+		for i, prize := range d.Payouts {
+			if prize < 0 {
+				return fmt.Errorf("payout for place %d must not be negative", i+1)
+			}
+		}
+	}
+	switch d.Schedule {
+	case "", "balanced", "round-robin":
+	default:
+		return fmt.Errorf("unknown schedule %q (use balanced or round-robin)", d.Schedule)
+	}
+
+	seen := make(map[string]bool)
+	for i := range d.Players {
+		seat := &d.Players[i]
+		if seat.Model == "" {
+			return fmt.Errorf("player %d has no model", i+1)
+		}
+		if seat.Name == "" {
+			seat.Name = seat.Model
+		}
+		if seat.Price == nil {
+			if price, ok := d.Prices[seat.Model]; ok {
+				seat.Price = &price
+			}
+		}
+		if err := seat.validateGeneration(); err != nil {
+			return err
+		}
+		if err := seat.validateFallback(); err != nil {
+			return err
+		}
+		if seat.Fallback != nil && seat.Fallback.Model != "" && seat.Fallback.Price == nil {
+			if price, ok := d.Prices[seat.Fallback.Model]; ok {
+				seat.Fallback.Price = &price
+			}
+		}
+		if seen[seat.Name] {
+			return fmt.Errorf("duplicate player name %q", seat.Name)
+		}
+		seen[seat.Name] = true
+	}
+	return nil
+}
+
+// Seats expands the experiment into one seat per variant, in label order
+func (e PromptExperiment) Seats() ([]SeatConfig, error) {
+	if e.Model == "" {
+		return nil, fmt.Errorf("prompt experiment has no model")
+	}
+	if len(e.Variants) < 2 {
+		return nil, fmt.Errorf("prompt experiment for %s needs at least 2 variants", e.Model)
+	}
+
+	name := e.Name
+	if name == "" {
+		name = e.Model
+	}
+
+	labels := make([]string, 0, len(e.Variants))
+	for label := range e.Variants {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	seats := make([]SeatConfig, len(labels))
+	for i, label := range labels {
+		seat := e.SeatConfig
+		seat.Name = fmt.Sprintf("%s [%s]", name, label)
+		seat.PromptTemplate = e.Variants[label]
+		seats[i] = seat
+	}
+	return seats, nil
+}
@@ -0,0 +1,28 @@
+package models
+
+import "testing"
+
+func TestValidateCashStacks(t *testing.T) {
+	tests := []struct {
+		cash CashConfig
+		ok   bool
+	}{
+		{CashConfig{}, true},
+		{CashConfig{BuyIn: 1000, TopUpTo: 2000}, true},
+		{CashConfig{BuyIn: MinCashStack * BigBlind}, true},
+		{CashConfig{BuyIn: 20}, false},
+		{CashConfig{BuyIn: 1000, TopUpTo: 50}, false},
+		{CashConfig{BuyIn: -1}, false},
+	}
+	for _, test := range tests {
+		cash := test.cash
+		def := TournamentDefinition{
+			Format:  "cash",
+			Cash:    &cash,
+			Players: []SeatConfig{{Name: "a", Model: "test/a"}, {Name: "b", Model: "test/b"}},
+		}
+		if err := def.Validate(); (err == nil) != test.ok {
+			t.Errorf("cash %+v: Validate() = %v, want ok %v", test.cash, err, test.ok)
+		}
+	}
+}
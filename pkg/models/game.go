@@ -29,23 +29,96 @@ type GameState struct {
 	BettingComplete   bool           `json:"bettingComplete"`
 	EliminatedPlayers []string       `json:"eliminatedPlayers"`
 	GameEnded         bool           `json:"gameEnded"`
+	HandActions       []ActionRecord `json:"handActions"`
+}
+
+// ActionRecord is one action taken during a hand, including posted blinds
+type ActionRecord struct {
+	HandNumber int    `json:"handNumber"`
+	Round      string `json:"round"`
+	Player     string `json:"player"`
+	Action     string `json:"action"`   // small_blind, big_blind, fold, check, call, raise
+	Amount     int    `json:"amount"`   // chips added to the pot by this action
+	TotalBet   int    `json:"totalBet"` // player's total bet this round after the action
+
+	// Set on the player's own decisions, not on blinds
+	Cards     []string `json:"cards,omitempty"`     // the player's hole cards
+	Reasoning string   `json:"reasoning,omitempty"` // the model's stated reason
+	Thinking  string   `json:"thinking,omitempty"`  // reasoning tokens returned by the provider
+	Fallback  string   `json:"fallback,omitempty"`  // the fallback agent that decided, if the model didn't
+}
+
+// HandRecord summarizes a completed hand
+type HandRecord struct {
+	HandNumber  int                 `json:"handNumber"`
+	Table       int                 `json:"table,omitempty"` // table number in a multi-table tournament
+	Board       []string            `json:"board"`
+	Actions     []ActionRecord      `json:"actions"`
+	Pot         int                 `json:"pot"`
+	BigBlind    int                 `json:"bigBlind"`
+	Winnings    map[string]int      `json:"winnings"`           // chips awarded from the pot, by player
+	Showdown    map[string][]string `json:"showdown,omitempty"` // hole cards revealed at showdown
+	WinningHand string              `json:"winningHand,omitempty"`
+	Eliminated  []string            `json:"eliminated,omitempty"` // players knocked out by this hand
+}
+
+// PlayerGameStats holds one player's decision statistics for a game
+type PlayerGameStats struct {
+	Decisions         int `json:"decisions"`
+	InvalidActions    int `json:"invalidActions"`    // rejected answers, including ones later corrected
+	DefaultedActions  int `json:"defaultedActions"`  // decisions where the model never gave a legal action
+	FallbackDecisions int `json:"fallbackDecisions"` // decisions made by the fallback agent
+
+	Requests         int     `json:"requests"`
+	PromptTokens     int     `json:"promptTokens"`
+	CompletionTokens int     `json:"completionTokens"`
+	Cost             float64 `json:"cost"` // estimated dollars
+	TotalLatencyMs   int64   `json:"totalLatencyMs"`
+
+	TotalDecisionMs int64 `json:"totalDecisionMs"` // wall-clock time spent deciding
+	MaxDecisionMs   int64 `json:"maxDecisionMs"`
+	Timeouts        int   `json:"timeouts"` // decisions that ran out of time
+	TimeBankUsedMs  int64 `json:"timeBankUsedMs"`
+
+	Style StyleCounts `json:"style"` // playing-style counts, filled in when the game ends
+
+	BuyIn    int `json:"buyIn"`            // chips bought in: the starting stack plus any top-ups
+	Rebuys   int `json:"rebuys,omitempty"` // cash game top-ups
+	NetChips int `json:"netChips"`         // final chips minus BuyIn, filled in when the game ends
+}
+
+// BBPer100 is the player's win rate in big blinds per 100 hands dealt
+func (s *PlayerGameStats) BBPer100(bigBlind int) float64 {
+	if bigBlind == 0 || s.Style.Hands == 0 {
+		return 0
+	}
+	return float64(s.NetChips) / float64(bigBlind) / float64(s.Style.Hands) * 100
 }
 
 type PlayerRanking struct {
-	Player   Player `json:"player"`
-	Rank     int    `json:"rank"`     // 1st, 2nd, 3rd, 4th place
-	Position string `json:"position"` // "Winner", "Runner-up", "3rd Place", "4th Place"
+	Player   Player  `json:"player"`
+	Rank     int     `json:"rank"`             // 1st, 2nd, 3rd, 4th place
+	Position string  `json:"position"`         // "Winner", "Runner-up", "3rd Place", "4th Place"
+	Payout   float64 `json:"payout,omitempty"` // prize won, or ICM equity if the game was stopped
 }
 
 type GameResult struct {
-	GameID        int             `json:"gameId"`
-	Winner        Player          `json:"winner"`
-	TotalHands    int             `json:"totalHands"`
-	AllPlayers    []Player        `json:"allPlayers"`
-	Eliminated    []string        `json:"eliminated"`
-	FinalChips    int             `json:"finalChips"`
-	GameDuration  string          `json:"gameDuration"`
-	StartTime     time.Time       `json:"startTime"`
-	EndTime       time.Time       `json:"endTime"`
-	PlayerRankings []PlayerRanking `json:"playerRankings"`
-}
\ No newline at end of file
+	GameID         int                         `json:"gameId"`
+	Winner         Player                      `json:"winner"`
+	TotalHands     int                         `json:"totalHands"`
+	AllPlayers     []Player                    `json:"allPlayers"`
+	Eliminated     []string                    `json:"eliminated"`
+	FinalChips     int                         `json:"finalChips"`
+	GameDuration   string                      `json:"gameDuration"`
+	StartTime      time.Time                   `json:"startTime"`
+	EndTime        time.Time                   `json:"endTime"`
+	PlayerRankings []PlayerRanking             `json:"playerRankings"`
+	PlayerStats    map[string]*PlayerGameStats `json:"playerStats"`
+	StartingChips  int                         `json:"startingChips"` // each player's stack at the start
+	Hands          []HandRecord                `json:"hands,omitempty"`
+	Seats          []SeatConfig                `json:"seats"`        // each seat's model and generation parameters
+	FallbackUsed   bool                        `json:"fallbackUsed"` // a fallback agent made at least one decision
+	BigBlind       int                         `json:"bigBlind"`
+	CashGame       bool                        `json:"cashGame,omitempty"`    // ranked by net chips rather than finishing order
+	Interrupted    bool                        `json:"interrupted,omitempty"` // stopped before it finished; ranked by stacks
+}
@@ -0,0 +1,22 @@
+package models
+
+import "testing"
+
+func TestBBPer100(t *testing.T) {
+	tests := []struct {
+		net, hands, bigBlind int
+		want                 float64
+	}{
+		{net: 500, hands: 50, bigBlind: 10, want: 100},
+		{net: -250, hands: 200, bigBlind: 10, want: -12.5},
+		{net: 0, hands: 40, bigBlind: 10, want: 0},
+		{net: 500, hands: 0, bigBlind: 10, want: 0},
+		{net: 500, hands: 50, bigBlind: 0, want: 0},
+	}
+	for _, test := range tests {
+		stats := PlayerGameStats{NetChips: test.net, Style: StyleCounts{Hands: test.hands}}
+		if got := stats.BBPer100(test.bigBlind); got != test.want {
+			t.Errorf("%+d chips over %d hands at %d: bb/100 = %v, want %v", test.net, test.hands, test.bigBlind, got, test.want)
+		}
+	}
+}
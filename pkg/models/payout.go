@@ -0,0 +1,171 @@
+package models
+
+import (
+	"math/bits"
+	"math/rand"
+)
+
+// Prize money. A payout table lists the prize for each finishing place,
+// first place first, in whatever units the user likes. Finished games pay
+// by place; games stopped early pay the players still in their ICM equity.
+
+const (
+	icmMaxStates = 1 << 18 // exact ICM up to this many sets of top finishers
+	icmSamples   = 20000   // finishing orders sampled beyond that
+)
+
+// Prize returns the payout for a finishing place, zero outside the money
+func Prize(payouts []float64, place int) float64 {
+	if place < 1 || place > len(payouts) {
+		return 0
+	}
+	return payouts[place-1]
+}
+
+// PayRankings sets each ranking's payout from its rank. Players sharing a
+// rank split the prizes for the places they cover.
+func PayRankings(rankings []PlayerRanking, payouts []float64) {
+	sharing := make(map[int]int)
+	for _, ranking := range rankings {
+		sharing[ranking.Rank]++
+	}
+	for i := range rankings {
+		rank, n := rankings[i].Rank, sharing[rankings[i].Rank]
+		total := 0.0
+		for place := rank; place < rank+n; place++ {
+			total += Prize(payouts, place)
+		}
+		rankings[i].Payout = total / float64(n)
+	}
+}
+
+// ICM returns each stack's prize equity under the Malmuth-Harville model:
+// a player finishes first with probability equal to their share of the
+// chips, second with their share of the chips left once first place is
+// taken, and so on. Small fields are computed exactly; large ones by
+// sampling finishing orders.
+func ICM(stacks []int, payouts []float64) []float64 {
+	n := len(stacks)
+	paid := min(len(payouts), n)
+	if paid == 0 {
+		return make([]float64, n)
+	}
+	if n > 64 || icmStates(n, paid) > icmMaxStates {
+		return sampleICM(stacks, payouts[:paid])
+	}
+
+	total := 0
+	for _, stack := range stacks {
+		total += stack
+	}
+
+	// layer maps each set of players who took the places so far to the
+	// probability that exactly they did
+	equity := make([]float64, n)
+	layer := map[uint64]float64{0: 1}
+	for place := 0; place < paid; place++ {
+		next := make(map[uint64]float64)
+		for taken, probability := range layer {
+			left, players := total, n-bits.OnesCount64(taken)
+			for i, stack := range stacks {
+				if taken&(1<<i) != 0 {
+					left -= stack
+				}
+			}
+			for i, stack := range stacks {
+				if taken&(1<<i) != 0 {
+					continue
+				}
+				p := probability / float64(players) // only empty stacks left
+				if left > 0 {
+					p = probability * float64(stack) / float64(left)
+				}
+				equity[i] += p * payouts[place]
+				next[taken|1<<i] += p
+			}
+		}
+		layer = next
+	}
+	return equity
+}
+
+// icmStates counts the sets of top finishers the exact calculation visits,
+// stopping once it passes the limit
+func icmStates(n, paid int) int {
+	states, subsets := 0, 1
+	for k := 0; k < paid && states <= icmMaxStates; k++ {
+		states += subsets
+		subsets = subsets * (n - k) / (k + 1)
+	}
+	return states
+}
+
+// sampleICM estimates ICM equity by drawing finishing orders with the
+// model's probabilities
+func sampleICM(stacks []int, payouts []float64) []float64 {
+	chips := 0
+	for _, stack := range stacks {
+		chips += stack
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	equity := make([]float64, len(stacks))
+	left := make([]int, len(stacks))
+	for sample := 0; sample < icmSamples; sample++ {
+		copy(left, stacks)
+		total := chips
+		out := make(map[int]bool, len(payouts))
+		for _, prize := range payouts {
+			winner := -1
+			if total > 0 {
+				pick := rng.Intn(total)
+				for i, stack := range left {
+					if pick < stack {
+						winner = i
+						break
+					}
+					pick -= stack
+				}
+			} else {
+				// Only empty stacks left: any of them, equally
+				pick := rng.Intn(len(stacks) - len(out))
+				for i := range stacks {
+					if out[i] {
+						continue
+					}
+					if pick == 0 {
+						winner = i
+						break
+					}
+					pick--
+				}
+			}
+			equity[winner] += prize / icmSamples
+			total -= left[winner]
+			left[winner] = 0
+			out[winner] = true
+		}
+	}
+	return equity
+}
+
+// RankUnfinished ranks the players of a game stopped before it finished,
+// as RankStandings does, and pays them. Busted players get the prize for
+// their place; players still in get their ICM equity in the prizes for the
+// places still open.
+func RankUnfinished(players []Player, places map[string]int, payouts []float64) []PlayerRanking {
+	rankings := RankStandings(players, places)
+	PayRankings(rankings, payouts)
+
+	var stacks []int
+	for _, ranking := range rankings {
+		if _, busted := places[ranking.Player.Name]; !busted {
+			stacks = append(stacks, ranking.Player.Chips)
+		}
+	}
+	equity := ICM(stacks, payouts[:min(len(payouts), len(stacks))])
+	for i := range stacks {
+		rankings[i].Payout = equity[i]
+	}
+	return rankings
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func closeTo(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func TestICMKnownValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		stacks  []int
+		payouts []float64
+		want    []float64
+	}{
+		{"heads-up, winner takes all", []int{30, 10}, []float64{100}, []float64{75, 25}},
+		{"heads-up, two prizes", []int{30, 10}, []float64{70, 30}, []float64{60, 40}},
+		{"three equal stacks", []int{20, 20, 20}, []float64{50, 30, 20}, []float64{100.0 / 3, 100.0 / 3, 100.0 / 3}},
+		{"50/30/20 stacks and prizes", []int{50, 30, 20}, []float64{50, 30, 20}, []float64{38.392857, 32.75, 28.857143}},
+		{"two prizes for four", []int{40, 30, 20, 10}, []float64{60, 40}, []float64{36.634921, 30.333333, 21.650794, 11.380952}},
+		{"more prizes than players", []int{10, 10}, []float64{60, 40, 20}, []float64{50, 50}},
+		{"busted stack", []int{30, 0}, []float64{70, 30}, []float64{70, 30}},
+		{"no prizes", []int{10, 20}, nil, []float64{0, 0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := ICM(test.stacks, test.payouts)
+			if len(got) != len(test.want) {
+				t.Fatalf("ICM returned %d equities, want %d", len(got), len(test.want))
+			}
+			for i := range got {
+				if !closeTo(got[i], test.want[i], 1e-5) {
+					t.Errorf("ICM(%v, %v) = %v, want %v", test.stacks, test.payouts, got, test.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestSampledICMAgreesWithExact(t *testing.T) {
+	stacks := []int{120, 80, 50, 30, 20}
+	payouts := []float64{50, 30, 20}
+
+	exact := ICM(stacks, payouts)
+	sampled := sampleICM(stacks, payouts)
+	for i := range exact {
+		// icmSamples draws put the standard error well under half a unit
+		if !closeTo(exact[i], sampled[i], 1) {
+			t.Errorf("stack %d: exact %.3f, sampled %.3f", stacks[i], exact[i], sampled[i])
+		}
+	}
+}
+
+func TestICMSamplesLargeFields(t *testing.T) {
+	stacks := make([]int, 40)
+	for i := range stacks {
+		stacks[i] = 10 + i
+	}
+	payouts := []float64{40, 25, 15, 10, 5, 3, 2}
+	if icmStates(len(stacks), len(payouts)) <= icmMaxStates {
+		t.Fatal("field is small enough for the exact calculation; make it bigger")
+	}
+
+	equity := ICM(stacks, payouts)
+	total := 0.0
+	for i, e := range equity {
+		total += e
+		if i > 0 && e+0.5 < equity[i-1] {
+			t.Errorf("stack %d has %.2f equity, less than the smaller stack before it (%.2f)", stacks[i], e, equity[i-1])
+		}
+	}
+	if !closeTo(total, 100, 1e-6) {
+		t.Errorf("equities sum to %.4f, want the prize pool 100", total)
+	}
+}
+
+func TestPayRankingsSplitsSharedPlaces(t *testing.T) {
+	rankings := []PlayerRanking{{Rank: 1}, {Rank: 2}, {Rank: 2}, {Rank: 4}}
+	PayRankings(rankings, []float64{50, 30, 20})
+
+	want := []float64{50, 25, 25, 0}
+	for i, ranking := range rankings {
+		if !closeTo(ranking.Payout, want[i], 1e-9) {
+			t.Errorf("rank %d paid %.2f, want %.2f", ranking.Rank, ranking.Payout, want[i])
+		}
+	}
+}
+
+func TestPrizeOutsideTheMoney(t *testing.T) {
+	payouts := []float64{70, 30}
+	for place, want := range map[int]float64{0: 0, 1: 70, 2: 30, 3: 0} {
+		if got := Prize(payouts, place); got != want {
+			t.Errorf("Prize(place %d) = %v, want %v", place, got, want)
+		}
+	}
+}
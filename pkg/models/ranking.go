@@ -0,0 +1,107 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Finishing order. A player's place is decided when they bust: the last
+// player standing wins, the last one out is runner-up, and so on. Players
+// busted by the same hand place by the stack they started it with, and share
+// a place when those stacks are equal.
+
+// PlaceBusts returns the players busted by a hand with their places, best
+// first. remaining is how many players were still in when it was dealt.
+func PlaceBusts(hand HandRecord, remaining int) []PlayerRanking {
+	if len(hand.Eliminated) == 0 {
+		return nil
+	}
+
+	// A busted player put their whole stack in
+	stacks := make(map[string]int)
+	for _, action := range hand.Actions {
+		stacks[action.Player] += action.Amount
+	}
+	busted := append([]string(nil), hand.Eliminated...)
+	sort.SliceStable(busted, func(i, j int) bool { return stacks[busted[i]] > stacks[busted[j]] })
+
+	rankings := make([]PlayerRanking, len(busted))
+	for i, name := range busted {
+		place := remaining - len(busted) + 1 + i
+		if i > 0 && stacks[name] == stacks[busted[i-1]] {
+			place = rankings[i-1].Rank
+		}
+		rankings[i] = PlayerRanking{Player: Player{Name: name}, Rank: place, Position: PlaceName(place)}
+	}
+	return rankings
+}
+
+// BustPlaces works out the places of everyone busted in a table's hands,
+// played in order by the given number of players
+func BustPlaces(players int, hands []HandRecord) map[string]int {
+	places := make(map[string]int)
+	remaining := players
+	for _, hand := range hands {
+		for _, ranking := range PlaceBusts(hand, remaining) {
+			places[ranking.Player.Name] = ranking.Rank
+		}
+		remaining -= len(hand.Eliminated)
+	}
+	return places
+}
+
+// RankStandings ranks players given the places of those who busted. Players
+// still in rank above them by stack, sharing a rank when stacks are equal;
+// at the end of a game that leaves the winner alone in first.
+func RankStandings(players []Player, places map[string]int) []PlayerRanking {
+	var in, out []Player
+	for _, player := range players {
+		if _, busted := places[player.Name]; busted {
+			out = append(out, player)
+		} else {
+			in = append(in, player)
+		}
+	}
+	sort.SliceStable(in, func(i, j int) bool { return in[i].Chips > in[j].Chips })
+	sort.SliceStable(out, func(i, j int) bool { return places[out[i].Name] < places[out[j].Name] })
+
+	rankings := make([]PlayerRanking, 0, len(players))
+	for i, player := range in {
+		rank := i + 1
+		if i > 0 && player.Chips == in[i-1].Chips {
+			rank = rankings[i-1].Rank
+		}
+		rankings = append(rankings, PlayerRanking{Player: player, Rank: rank, Position: PlaceName(rank)})
+	}
+	for _, player := range out {
+		place := places[player.Name]
+		rankings = append(rankings, PlayerRanking{Player: player, Rank: place, Position: PlaceName(place)})
+	}
+	return rankings
+}
+
+// PlaceName labels a finishing place: "Winner", "Runner-up", "3rd Place"...
+func PlaceName(place int) string {
+	switch place {
+	case 1:
+		return "Winner"
+	case 2:
+		return "Runner-up"
+	}
+	return Ordinal(place) + " Place"
+}
+
+// Ordinal writes n as 1st, 2nd, 3rd...
+func Ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// placings writes rankings as "name:rank" in order
+func placings(rankings []PlayerRanking) string {
+	var parts []string
+	for _, ranking := range rankings {
+		parts = append(parts, fmt.Sprintf("%s:%d", ranking.Player.Name, ranking.Rank))
+	}
+	return strings.Join(parts, " ")
+}
+
+// allIn is a hand in which each player put in the given amount
+func allIn(eliminated []string, amounts map[string]int) HandRecord {
+	hand := HandRecord{Eliminated: eliminated}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		if amount, ok := amounts[name]; ok {
+			hand.Actions = append(hand.Actions, ActionRecord{Player: name, Action: "raise", Amount: amount})
+		}
+	}
+	return hand
+}
+
+func TestPlaceBusts(t *testing.T) {
+	tests := []struct {
+		name      string
+		hand      HandRecord
+		remaining int
+		want      string
+	}{
+		{"no busts", allIn(nil, map[string]int{"a": 20, "b": 20}), 4, ""},
+		{"one bust", allIn([]string{"b"}, map[string]int{"a": 30, "b": 30}), 4, "b:4"},
+		{"bigger stack places higher", allIn([]string{"c", "b"}, map[string]int{"a": 40, "b": 15, "c": 40}), 4, "c:3 b:4"},
+		{"equal stacks share a place", allIn([]string{"b", "c"}, map[string]int{"a": 20, "b": 20, "c": 20}), 5, "b:4 c:4"},
+		{"share below a bigger stack", allIn([]string{"d", "b", "c"}, map[string]int{"a": 50, "b": 10, "c": 10, "d": 30}), 4, "d:2 b:3 c:3"},
+		{"heads-up bust is runner-up", allIn([]string{"a"}, map[string]int{"a": 25, "b": 25}), 2, "a:2"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rankings := PlaceBusts(test.hand, test.remaining)
+			if got := placings(rankings); got != test.want {
+				t.Errorf("places %q, want %q", got, test.want)
+			}
+			for _, ranking := range rankings {
+				if ranking.Position != PlaceName(ranking.Rank) {
+					t.Errorf("%s placed %d is labelled %q", ranking.Player.Name, ranking.Rank, ranking.Position)
+				}
+			}
+		})
+	}
+}
+
+func TestBustPlaces(t *testing.T) {
+	hands := []HandRecord{
+		allIn([]string{"d"}, map[string]int{"a": 20, "d": 20}),
+		allIn(nil, map[string]int{"a": 10, "b": 10}),
+		allIn([]string{"b", "c"}, map[string]int{"a": 30, "b": 30, "c": 30}),
+	}
+	places := BustPlaces(4, hands)
+	if len(places) != 3 || places["d"] != 4 || places["b"] != 2 || places["c"] != 2 {
+		t.Errorf("places %v, want d 4th and b and c sharing 2nd", places)
+	}
+}
+
+func TestRankStandings(t *testing.T) {
+	tests := []struct {
+		name    string
+		players []Player
+		places  map[string]int
+		want    string
+	}{
+		{
+			"finished game",
+			[]Player{{Name: "a", Chips: 0}, {Name: "b", Chips: 80}, {Name: "c", Chips: 0}, {Name: "d", Chips: 0}},
+			map[string]int{"a": 3, "c": 2, "d": 4},
+			"b:1 c:2 a:3 d:4",
+		},
+		{
+			"players still in rank by stack",
+			[]Player{{Name: "a", Chips: 25}, {Name: "b", Chips: 45}, {Name: "c", Chips: 10}, {Name: "d", Chips: 0}},
+			map[string]int{"d": 4},
+			"b:1 a:2 c:3 d:4",
+		},
+		{
+			"equal stacks share a rank",
+			[]Player{{Name: "a", Chips: 30}, {Name: "b", Chips: 30}, {Name: "c", Chips: 20}, {Name: "d", Chips: 0}},
+			map[string]int{"d": 4},
+			"a:1 b:1 c:3 d:4",
+		},
+		{
+			"shared bust places",
+			[]Player{{Name: "a", Chips: 80}, {Name: "b", Chips: 0}, {Name: "c", Chips: 0}, {Name: "d", Chips: 0}},
+			map[string]int{"b": 2, "c": 2, "d": 4},
+			"a:1 b:2 c:2 d:4",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := placings(RankStandings(test.players, test.places)); got != test.want {
+				t.Errorf("standings %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	for n, want := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 102: "102nd", 111: "111th"} {
+		if got := Ordinal(n); got != want {
+			t.Errorf("Ordinal(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
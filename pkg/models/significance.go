@@ -0,0 +1,190 @@
+package models
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Sample-size uncertainty for tournament results: confidence intervals for
+// each player's numbers, and pairwise tests of who finishes ahead of whom.
+
+const (
+	confidenceZ       = 1.96 // 95%
+	bootstrapSamples  = 2000
+	bootstrapSeed     = 1 // fixed so the same results always give the same intervals
+	significanceLevel = 0.05
+)
+
+// PairwiseResult compares two players over the games they both played
+type PairwiseResult struct {
+	PlayerA string `json:"playerA"`
+	PlayerB string `json:"playerB"`
+	AAhead  int    `json:"aAhead"` // games A finished ahead of B
+	BAhead  int    `json:"bAhead"`
+	Ties    int    `json:"ties"`
+
+	// Two-sided sign test p-value, and whether the difference holds at
+	// p<0.05 after Holm correction for the number of pairs compared
+	PValue      float64 `json:"pValue"`
+	Significant bool    `json:"significant"`
+}
+
+// Better returns the player who finished ahead more often ("" if even)
+func (p PairwiseResult) Better() string {
+	switch {
+	case p.AAhead > p.BAhead:
+		return p.PlayerA
+	case p.BAhead > p.AAhead:
+		return p.PlayerB
+	}
+	return ""
+}
+
+// ComputeSignificance fills in every player's confidence intervals and the
+// pairwise comparisons from the game results so far
+func (tr *TournamentResult) ComputeSignificance() {
+	ranks := make(map[string][]float64)
+	chips := make(map[string][]float64)
+	for _, result := range tr.GameResults {
+		for _, ranking := range result.PlayerRankings {
+			name := ranking.Player.Name
+			ranks[name] = append(ranks[name], float64(ranking.Rank))
+			chips[name] = append(chips[name], float64(ranking.Player.Chips))
+		}
+	}
+
+	for name, stats := range tr.PlayerStats {
+		low, high := WilsonInterval(stats.Wins, stats.TotalGames)
+		stats.WinRateCI = [2]float64{low * 100, high * 100}
+
+		// Each player gets their own draws, over their values in a fixed
+		// order, so intervals don't depend on map or game order
+		sort.Float64s(ranks[name])
+		sort.Float64s(chips[name])
+		rng := bootstrapRand(name)
+		stats.AvgRankCI = BootstrapMeanInterval(ranks[name], rng)
+		stats.AvgChipsCI = BootstrapMeanInterval(chips[name], rng)
+	}
+
+	tr.Pairwise = tr.pairwiseComparisons()
+}
+
+// bootstrapRand returns the random source for a player's bootstrap samples
+func bootstrapRand(name string) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return rand.New(rand.NewSource(bootstrapSeed ^ int64(h.Sum64())))
+}
+
+// pairwiseComparisons runs a sign test for every pair of players on their
+// relative finishing position, then applies the Holm correction
+func (tr *TournamentResult) pairwiseComparisons() []PairwiseResult {
+	names := make([]string, 0, len(tr.PlayerStats))
+	for name := range tr.PlayerStats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var pairs []PairwiseResult
+	for i := 0; i < len(names); i++ {
+		for j := i + 1; j < len(names); j++ {
+			pair := PairwiseResult{PlayerA: names[i], PlayerB: names[j]}
+			for _, result := range tr.GameResults {
+				rankA, rankB := 0, 0
+				for _, ranking := range result.PlayerRankings {
+					switch ranking.Player.Name {
+					case pair.PlayerA:
+						rankA = ranking.Rank
+					case pair.PlayerB:
+						rankB = ranking.Rank
+					}
+				}
+				switch {
+				case rankA == 0 || rankB == 0:
+					// Not at the same table
+				case rankA < rankB:
+					pair.AAhead++
+				case rankB < rankA:
+					pair.BAhead++
+				default:
+					pair.Ties++
+				}
+			}
+			pair.PValue = SignTest(pair.AAhead, pair.BAhead)
+			pairs = append(pairs, pair)
+		}
+	}
+
+	// Holm: compare the k-th smallest p-value with alpha/(m-k) and stop at
+	// the first that fails
+	order := make([]int, len(pairs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return pairs[order[a]].PValue < pairs[order[b]].PValue })
+	for k, index := range order {
+		if pairs[index].PValue > significanceLevel/float64(len(pairs)-k) {
+			break
+		}
+		pairs[index].Significant = true
+	}
+	return pairs
+}
+
+// WilsonInterval is the 95% Wilson score interval for a proportion
+func WilsonInterval(successes, n int) (low, high float64) {
+	if n == 0 {
+		return 0, 1
+	}
+	z2 := confidenceZ * confidenceZ
+	p := float64(successes) / float64(n)
+	nf := float64(n)
+
+	center := (p + z2/(2*nf)) / (1 + z2/nf)
+	margin := confidenceZ / (1 + z2/nf) * math.Sqrt(p*(1-p)/nf+z2/(4*nf*nf))
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// BootstrapMeanInterval is the 95% percentile bootstrap interval for the
+// mean of values
+func BootstrapMeanInterval(values []float64, rng *rand.Rand) [2]float64 {
+	if len(values) == 0 {
+		return [2]float64{}
+	}
+
+	means := make([]float64, bootstrapSamples)
+	for i := range means {
+		sum := 0.0
+		for range values {
+			sum += values[rng.Intn(len(values))]
+		}
+		means[i] = sum / float64(len(values))
+	}
+	sort.Float64s(means)
+
+	low := means[int(0.025*float64(bootstrapSamples))]
+	high := means[int(0.975*float64(bootstrapSamples))-1]
+	return [2]float64{low, high}
+}
+
+// SignTest is the exact two-sided binomial sign test p-value for a wins
+// against b wins (ties excluded)
+func SignTest(a, b int) float64 {
+	n := a + b
+	if n == 0 {
+		return 1
+	}
+	k := min(a, b)
+
+	// P(X <= k) for X ~ Binomial(n, 1/2), summed in log space
+	tail := 0.0
+	for i := 0; i <= k; i++ {
+		logChoose, _ := math.Lgamma(float64(n + 1))
+		li, _ := math.Lgamma(float64(i + 1))
+		lni, _ := math.Lgamma(float64(n - i + 1))
+		tail += math.Exp(logChoose - li - lni - float64(n)*math.Ln2)
+	}
+	return math.Min(1, 2*tail)
+}
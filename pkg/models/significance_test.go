@@ -0,0 +1,58 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+// fourPlayerGames makes n games whose finishing orders rotate through the
+// players, with some variation so the bootstrap has work to do
+func fourPlayerGames(n int) []*GameResult {
+	names := []string{"a", "b", "c", "d"}
+	var games []*GameResult
+	for g := 0; g < n; g++ {
+		result := &GameResult{GameID: g + 1, PlayerStats: make(map[string]*PlayerGameStats)}
+		for place := 0; place < len(names); place++ {
+			name := names[(g*g+place)%len(names)]
+			chips := (g*37 + place*101) % 97
+			result.PlayerRankings = append(result.PlayerRankings, PlayerRanking{
+				Player:   Player{Name: name, Chips: chips},
+				Rank:     place + 1,
+				Position: Ordinal(place + 1),
+			})
+			result.PlayerStats[name] = &PlayerGameStats{}
+		}
+		result.Winner = result.PlayerRankings[0].Player
+		games = append(games, result)
+	}
+	return games
+}
+
+func intervals(tr *TournamentResult) string {
+	var out string
+	for _, name := range []string{"a", "b", "c", "d"} {
+		stats := tr.PlayerStats[name]
+		out += fmt.Sprintf("%s %v %v %v\n", name, stats.WinRateCI, stats.AvgRankCI, stats.AvgChipsCI)
+	}
+	return out
+}
+
+func TestComputeSignificanceIsReproducible(t *testing.T) {
+	games := fourPlayerGames(12)
+
+	forward := NewTournamentResult(len(games))
+	for _, result := range games {
+		forward.AddGameResult(result)
+	}
+	want := intervals(forward)
+
+	for run := 0; run < 20; run++ {
+		tr := NewTournamentResult(len(games))
+		for i := len(games) - 1; i >= 0; i-- {
+			tr.AddGameResult(games[i])
+		}
+		if got := intervals(tr); got != want {
+			t.Fatalf("run %d, games in reverse order:\n%s\nwant\n%s", run, got, want)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package models
+
+// Playing-style statistics in the usual tracker terms, counted from the
+// recorded hands. Counts add up across games; the percentages are derived
+// from them so a tournament's numbers weight every hand equally.
+
+// StyleCounts are the raw events and opportunities behind StyleStats
+type StyleCounts struct {
+	Hands          int     `json:"hands"`          // hands dealt in
+	VPIP           int     `json:"vpip"`           // hands with a voluntary preflop call or raise
+	PFR            int     `json:"pfr"`            // hands with a preflop raise
+	ThreeBetChance int     `json:"threeBetChance"` // preflop decisions facing a single raise
+	ThreeBets      int     `json:"threeBets"`
+	Aggressive     int     `json:"aggressive"` // postflop bets and raises
+	Calls          int     `json:"calls"`      // postflop calls
+	FacedRaise     int     `json:"facedRaise"` // decisions facing another player's raise
+	FoldedToRaise  int     `json:"foldedToRaise"`
+	SawFlop        int     `json:"sawFlop"`
+	Showdowns      int     `json:"showdowns"`
+	ShowdownsWon   int     `json:"showdownsWon"`
+	CBetChance     int     `json:"cbetChance"` // flops as preflop aggressor with no bet in front
+	CBets          int     `json:"cbets"`
+	Raises         int     `json:"raises"`
+	RaiseToBB      float64 `json:"raiseToBB"` // sum of raise-to amounts in big blinds
+}
+
+// StyleStats are the percentages and ratios shown to users
+type StyleStats struct {
+	Hands       int     `json:"hands"`
+	VPIP        float64 `json:"vpip"`        // % of hands
+	PFR         float64 `json:"pfr"`         // % of hands
+	ThreeBet    float64 `json:"threeBet"`    // % of chances
+	AF          float64 `json:"af"`          // postflop (bets+raises)/calls
+	FoldToRaise float64 `json:"foldToRaise"` // % of times facing a raise
+	WTSD        float64 `json:"wtsd"`        // % of flops seen that reached showdown
+	WSD         float64 `json:"wsd"`         // % of showdowns won
+	CBet        float64 `json:"cbet"`        // % of chances
+	AvgRaiseBB  float64 `json:"avgRaiseBB"`  // average raise-to size in big blinds
+}
+
+// Add accumulates other into c
+func (c *StyleCounts) Add(other StyleCounts) {
+	c.Hands += other.Hands
+	c.VPIP += other.VPIP
+	c.PFR += other.PFR
+	c.ThreeBetChance += other.ThreeBetChance
+	c.ThreeBets += other.ThreeBets
+	c.Aggressive += other.Aggressive
+	c.Calls += other.Calls
+	c.FacedRaise += other.FacedRaise
+	c.FoldedToRaise += other.FoldedToRaise
+	c.SawFlop += other.SawFlop
+	c.Showdowns += other.Showdowns
+	c.ShowdownsWon += other.ShowdownsWon
+	c.CBetChance += other.CBetChance
+	c.CBets += other.CBets
+	c.Raises += other.Raises
+	c.RaiseToBB += other.RaiseToBB
+}
+
+// Stats turns the counts into percentages and ratios
+func (c StyleCounts) Stats() StyleStats {
+	stats := StyleStats{
+		Hands:       c.Hands,
+		VPIP:        percent(c.VPIP, c.Hands),
+		PFR:         percent(c.PFR, c.Hands),
+		ThreeBet:    percent(c.ThreeBets, c.ThreeBetChance),
+		FoldToRaise: percent(c.FoldedToRaise, c.FacedRaise),
+		WTSD:        percent(c.Showdowns, c.SawFlop),
+		WSD:         percent(c.ShowdownsWon, c.Showdowns),
+		CBet:        percent(c.CBets, c.CBetChance),
+	}
+	switch {
+	case c.Calls > 0:
+		stats.AF = float64(c.Aggressive) / float64(c.Calls)
+	case c.Aggressive > 0:
+		// Never called: report the aggression count itself
+		stats.AF = float64(c.Aggressive)
+	}
+	if c.Raises > 0 {
+		stats.AvgRaiseBB = c.RaiseToBB / float64(c.Raises)
+	}
+	return stats
+}
+
+func percent(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d) * 100
+}
+
+// CountStyle counts every player's style events over the given hands
+func CountStyle(hands []HandRecord) map[string]*StyleCounts {
+	counts := make(map[string]*StyleCounts)
+	get := func(name string) *StyleCounts {
+		if counts[name] == nil {
+			counts[name] = &StyleCounts{}
+		}
+		return counts[name]
+	}
+
+	for _, hand := range hands {
+		dealt := make(map[string]bool)
+		vpip := make(map[string]bool)
+		pfr := make(map[string]bool)
+		foldedPreflop := make(map[string]bool)
+
+		round := ""
+		raises := 0      // raises so far this round
+		lastRaiser := "" // who made the latest raise this round
+		aggressor := ""  // last preflop raiser
+		flopBet := false // someone has bet the flop
+		for _, action := range hand.Actions {
+			c := get(action.Player)
+			dealt[action.Player] = true
+
+			if action.Round != round {
+				round = action.Round
+				raises = 0
+				lastRaiser = ""
+			}
+			if action.Action == "small_blind" || action.Action == "big_blind" {
+				continue
+			}
+
+			facingRaise := raises > 0 && lastRaiser != action.Player
+			if facingRaise {
+				c.FacedRaise++
+				if action.Action == "fold" {
+					c.FoldedToRaise++
+				}
+			}
+
+			if round == "preflop" {
+				if raises == 1 && facingRaise {
+					c.ThreeBetChance++
+					if action.Action == "raise" {
+						c.ThreeBets++
+					}
+				}
+				switch action.Action {
+				case "call":
+					vpip[action.Player] = true
+				case "raise":
+					vpip[action.Player] = true
+					pfr[action.Player] = true
+					aggressor = action.Player
+				case "fold":
+					foldedPreflop[action.Player] = true
+				}
+			} else {
+				switch action.Action {
+				case "raise":
+					c.Aggressive++
+				case "call":
+					c.Calls++
+				}
+				if round == "flop" && !flopBet {
+					if action.Player == aggressor {
+						c.CBetChance++
+						if action.Action == "raise" {
+							c.CBets++
+						}
+					}
+					if action.Action == "raise" {
+						flopBet = true
+					}
+				}
+			}
+
+			if action.Action == "raise" {
+				raises++
+				lastRaiser = action.Player
+				c.Raises++
+				if hand.BigBlind > 0 {
+					c.RaiseToBB += float64(action.TotalBet) / float64(hand.BigBlind)
+				}
+			}
+		}
+
+		for name := range dealt {
+			c := get(name)
+			c.Hands++
+			if vpip[name] {
+				c.VPIP++
+			}
+			if pfr[name] {
+				c.PFR++
+			}
+			// A player at a showdown before the flop, all in, would have seen
+			// it had the board been run out
+			_, showdown := hand.Showdown[name]
+			if (len(hand.Board) >= 3 && !foldedPreflop[name]) || showdown {
+				c.SawFlop++
+			}
+		}
+		for name := range hand.Showdown {
+			c := get(name)
+			c.Showdowns++
+			if hand.Winnings[name] > 0 {
+				c.ShowdownsWon++
+			}
+		}
+	}
+	return counts
+}
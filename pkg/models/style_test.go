@@ -0,0 +1,82 @@
+package models
+
+import "testing"
+
+func TestCountStyle(t *testing.T) {
+	hands := []HandRecord{
+		{
+			// c opens, b 3-bets and c-bets the flop, c calls down and loses
+			HandNumber: 1,
+			Board:      []string{"2♠", "7♥", "9♦", "J♣", "K♠"},
+			BigBlind:   10,
+			Actions: []ActionRecord{
+				{Round: "preflop", Player: "a", Action: "small_blind", Amount: 5, TotalBet: 5},
+				{Round: "preflop", Player: "b", Action: "big_blind", Amount: 10, TotalBet: 10},
+				{Round: "preflop", Player: "c", Action: "raise", Amount: 30, TotalBet: 30},
+				{Round: "preflop", Player: "a", Action: "fold"},
+				{Round: "preflop", Player: "b", Action: "raise", Amount: 50, TotalBet: 60},
+				{Round: "preflop", Player: "c", Action: "call", Amount: 30, TotalBet: 60},
+				{Round: "flop", Player: "b", Action: "raise", Amount: 20, TotalBet: 20},
+				{Round: "flop", Player: "c", Action: "call", Amount: 20, TotalBet: 20},
+				{Round: "turn", Player: "b", Action: "check"},
+				{Round: "turn", Player: "c", Action: "check"},
+				{Round: "river", Player: "b", Action: "check"},
+				{Round: "river", Player: "c", Action: "check"},
+			},
+			Winnings: map[string]int{"b": 165},
+			Showdown: map[string][]string{"b": {"A♠", "A♥"}, "c": {"Q♦", "Q♣"}},
+		},
+		{
+			// a shoves preflop and b calls; the pot is split with no board
+			HandNumber: 2,
+			BigBlind:   10,
+			Actions: []ActionRecord{
+				{Round: "preflop", Player: "a", Action: "small_blind", Amount: 5, TotalBet: 5},
+				{Round: "preflop", Player: "b", Action: "big_blind", Amount: 10, TotalBet: 10},
+				{Round: "preflop", Player: "a", Action: "raise", Amount: 15, TotalBet: 20},
+				{Round: "preflop", Player: "b", Action: "call", Amount: 10, TotalBet: 20},
+			},
+			Winnings: map[string]int{"a": 20, "b": 20},
+			Showdown: map[string][]string{"a": {"8♠", "8♥"}, "b": {"A♦", "K♦"}},
+		},
+		{
+			// Folded round to the big blind
+			HandNumber: 3,
+			BigBlind:   10,
+			Actions: []ActionRecord{
+				{Round: "preflop", Player: "c", Action: "small_blind", Amount: 5, TotalBet: 5},
+				{Round: "preflop", Player: "a", Action: "big_blind", Amount: 10, TotalBet: 10},
+				{Round: "preflop", Player: "b", Action: "fold"},
+				{Round: "preflop", Player: "c", Action: "fold"},
+			},
+			Winnings: map[string]int{"a": 15},
+		},
+	}
+
+	want := map[string]StyleCounts{
+		"a": {Hands: 3, VPIP: 1, PFR: 1, ThreeBetChance: 1, FacedRaise: 1, FoldedToRaise: 1,
+			SawFlop: 1, Showdowns: 1, ShowdownsWon: 1, Raises: 1, RaiseToBB: 2},
+		"b": {Hands: 3, VPIP: 2, PFR: 1, ThreeBetChance: 2, ThreeBets: 1, Aggressive: 1, FacedRaise: 2,
+			SawFlop: 2, Showdowns: 2, ShowdownsWon: 2, CBetChance: 1, CBets: 1, Raises: 2, RaiseToBB: 8},
+		"c": {Hands: 2, VPIP: 1, PFR: 1, Calls: 1, FacedRaise: 2,
+			SawFlop: 1, Showdowns: 1, Raises: 1, RaiseToBB: 3},
+	}
+
+	counts := CountStyle(hands)
+	if len(counts) != len(want) {
+		t.Fatalf("counted %d players, want %d", len(counts), len(want))
+	}
+	for name, w := range want {
+		if got := *counts[name]; got != w {
+			t.Errorf("%s: counts = %+v, want %+v", name, got, w)
+		}
+	}
+
+	stats := counts["b"].Stats()
+	if stats.WTSD != 100 || stats.WSD != 100 || stats.ThreeBet != 50 || stats.CBet != 100 {
+		t.Errorf("b: stats = %+v, want WTSD 100, W$SD 100, 3-bet 50, c-bet 100", stats)
+	}
+	if stats := counts["c"].Stats(); stats.WTSD != 100 || stats.WSD != 0 || stats.AF != 0 {
+		t.Errorf("c: stats = %+v, want WTSD 100, W$SD 0, AF 0", stats)
+	}
+}
@@ -5,6 +5,7 @@ import "time"
 // PlayerStats holds aggregated statistics for a player across multiple games
 type PlayerStats struct {
 	Name         string  `json:"name"`
+	Parameters   string  `json:"parameters,omitempty"` // non-default generation parameters
 	TotalGames   int     `json:"totalGames"`
 	Wins         int     `json:"wins"`
 	SecondPlace  int     `json:"secondPlace"`
@@ -14,6 +15,50 @@ type PlayerStats struct {
 	AvgRank      float64 `json:"avgRank"`
 	TotalChips   int     `json:"totalChips"`   // Total chips won across all games
 	AvgChips     float64 `json:"avgChips"`     // Average final chips per game
+
+	// 95% confidence intervals [low, high]: Wilson for the win rate,
+	// bootstrap for average rank and chips
+	WinRateCI  [2]float64 `json:"winRateCI"`
+	AvgRankCI  [2]float64 `json:"avgRankCI"`
+	AvgChipsCI [2]float64 `json:"avgChipsCI"`
+
+	Decisions         int     `json:"decisions"`
+	InvalidActions    int     `json:"invalidActions"`
+	DefaultedActions  int     `json:"defaultedActions"`
+	FallbackDecisions int     `json:"fallbackDecisions"`
+	InvalidActionRate float64 `json:"invalidActionRate"` // Invalid answers per 100 decisions
+
+	Requests         int     `json:"requests"`
+	PromptTokens     int     `json:"promptTokens"`
+	CompletionTokens int     `json:"completionTokens"`
+	TotalCost        float64 `json:"totalCost"`      // Estimated dollars across all games
+	TotalLatencyMs   int64   `json:"totalLatencyMs"`
+	AvgLatencyMs     float64 `json:"avgLatencyMs"`   // Average per request
+
+	TotalDecisionMs int64   `json:"totalDecisionMs"`
+	AvgDecisionMs   float64 `json:"avgDecisionMs"` // Average wall-clock time per decision
+	MaxDecisionMs   int64   `json:"maxDecisionMs"`
+	Timeouts        int     `json:"timeouts"`
+
+	// Playing style over every hand played in the tournament
+	StyleCounts StyleCounts `json:"styleCounts"`
+	Style       StyleStats  `json:"style"`
+
+	NetChips         int     `json:"netChips"`       // Chips won minus chips bought in
+	ChipsPerDollar   float64 `json:"chipsPerDollar"` // NetChips / TotalCost
+
+	// Prize money from the payout table, counting ICM equity for games
+	// stopped before they finished
+	Winnings         float64 `json:"winnings"`
+	AvgWinnings      float64 `json:"avgWinnings"` // per game, stopped games included
+	InTheMoney       int     `json:"inTheMoney"`  // finished games with a prize
+	InterruptedGames int     `json:"interruptedGames,omitempty"`
+
+	// Cash game results: win rate in big blinds per 100 hands dealt
+	Rebuys      int     `json:"rebuys,omitempty"`
+	HandsPlayed int     `json:"handsPlayed"`
+	NetBB       float64 `json:"netBB"`
+	BBPer100    float64 `json:"bbPer100"`
 }
 
 // TournamentResult holds aggregated results from multiple games
@@ -25,7 +70,23 @@ type TournamentResult struct {
 	TournamentDuration string               `json:"tournamentDuration"`
 	GameResults      []*GameResult          `json:"gameResults"`
 	PlayerStats      map[string]*PlayerStats `json:"playerStats"`
-	OverallWinner    string                 `json:"overallWinner"` // Player with most wins
+	OverallWinner    string                 `json:"overallWinner"` // Player with most wins, or most net chips in cash games
+	CashGame         bool                   `json:"cashGame,omitempty"`
+	FallbackGames    int                    `json:"fallbackGames"` // Games where a fallback agent stood in
+	Pairwise         []PairwiseResult       `json:"pairwise,omitempty"` // Who finishes ahead of whom, with significance
+	HeadToHead       *HeadToHead            `json:"headToHead,omitempty"`
+	Payouts          []float64              `json:"payouts,omitempty"` // prize for each finishing place
+	InterruptedGames []*GameResult          `json:"interruptedGames,omitempty"` // stopped before they finished, scored by ICM
+}
+
+// HeadToHead is a set of player-by-player matrices, each indexed
+// [row][column] by player name
+type HeadToHead struct {
+	Players       []string                      `json:"players"`
+	Games         map[string]map[string]int     `json:"games"`         // games the two played together
+	ChipsWon      map[string]map[string]float64 `json:"chipsWon"`      // net chips row won from column in pots both put chips in
+	Eliminations  map[string]map[string]int     `json:"eliminations"`  // times row knocked out column
+	FinishedAhead map[string]map[string]int     `json:"finishedAhead"` // games row finished ahead of column
 }
 
 // NewTournamentResult creates a new tournament result tracker
@@ -43,6 +104,12 @@ func NewTournamentResult(totalGames int) *TournamentResult {
 func (tr *TournamentResult) AddGameResult(result *GameResult) {
 	tr.GameResults = append(tr.GameResults, result)
 	tr.CompletedGames++
+	if result.FallbackUsed {
+		tr.FallbackGames++
+	}
+	if result.CashGame {
+		tr.CashGame = true
+	}
 	
 	// Update player statistics
 	for _, ranking := range result.PlayerRankings {
@@ -61,8 +128,25 @@ func (tr *TournamentResult) AddGameResult(result *GameResult) {
 		}
 		
 		stats := tr.PlayerStats[playerName]
+		stats.Winnings += ranking.Payout
+		if ranking.Payout > 0 {
+			stats.InTheMoney++
+		}
+		for _, seat := range result.Seats {
+			if seat.Name == playerName {
+				stats.Parameters = seat.GenerationParams()
+			}
+		}
 		stats.TotalGames++
 		stats.TotalChips += ranking.Player.Chips
+		net := ranking.Player.Chips - result.StartingChips
+		if gameStats, ok := result.PlayerStats[playerName]; ok && gameStats.BuyIn > 0 {
+			net = gameStats.NetChips
+		}
+		stats.NetChips += net
+		if result.BigBlind > 0 {
+			stats.NetBB += float64(net) / float64(result.BigBlind)
+		}
 		
 		// Update placement counts
 		switch ranking.Rank {
@@ -76,12 +160,47 @@ func (tr *TournamentResult) AddGameResult(result *GameResult) {
 			stats.FourthPlace++
 		}
 		
+		// Decision quality
+		if gameStats, ok := result.PlayerStats[playerName]; ok {
+			stats.Decisions += gameStats.Decisions
+			stats.InvalidActions += gameStats.InvalidActions
+			stats.DefaultedActions += gameStats.DefaultedActions
+			stats.FallbackDecisions += gameStats.FallbackDecisions
+			stats.Requests += gameStats.Requests
+			stats.PromptTokens += gameStats.PromptTokens
+			stats.CompletionTokens += gameStats.CompletionTokens
+			stats.TotalCost += gameStats.Cost
+			stats.TotalLatencyMs += gameStats.TotalLatencyMs
+			stats.TotalDecisionMs += gameStats.TotalDecisionMs
+			stats.MaxDecisionMs = max(stats.MaxDecisionMs, gameStats.MaxDecisionMs)
+			stats.Timeouts += gameStats.Timeouts
+			stats.StyleCounts.Add(gameStats.Style)
+			stats.Style = stats.StyleCounts.Stats()
+			stats.Rebuys += gameStats.Rebuys
+			stats.HandsPlayed += gameStats.Style.Hands
+		}
+		if stats.HandsPlayed > 0 {
+			stats.BBPer100 = stats.NetBB / float64(stats.HandsPlayed) * 100
+		}
+		if stats.Decisions > 0 {
+			stats.InvalidActionRate = float64(stats.InvalidActions) / float64(stats.Decisions) * 100
+		}
+		if stats.Requests > 0 {
+			stats.AvgLatencyMs = float64(stats.TotalLatencyMs) / float64(stats.Requests)
+		}
+		if stats.Decisions > 0 {
+			stats.AvgDecisionMs = float64(stats.TotalDecisionMs) / float64(stats.Decisions)
+		}
+		if stats.TotalCost > 0 {
+			stats.ChipsPerDollar = float64(stats.NetChips) / stats.TotalCost
+		}
+		
 		// Recalculate averages
 		stats.WinRate = float64(stats.Wins) / float64(stats.TotalGames) * 100
 		stats.AvgChips = float64(stats.TotalChips) / float64(stats.TotalGames)
-		stats.AvgRank = (float64(stats.Wins)*1 + float64(stats.SecondPlace)*2 + 
-						 float64(stats.ThirdPlace)*3 + float64(stats.FourthPlace)*4) / 
-						 float64(stats.TotalGames)
+		stats.AvgWinnings = stats.Winnings / float64(stats.TotalGames+stats.InterruptedGames)
+		// Running mean, so places past 4th (multi-table fields) count too
+		stats.AvgRank += (float64(ranking.Rank) - stats.AvgRank) / float64(stats.TotalGames)
 	}
 	
 	// Update overall winner if tournament is complete
@@ -89,6 +208,25 @@ func (tr *TournamentResult) AddGameResult(result *GameResult) {
 		tr.EndTime = time.Now()
 		tr.TournamentDuration = tr.EndTime.Sub(tr.StartTime).String()
 		tr.updateOverallWinner()
+		tr.ComputeSignificance()
+	}
+}
+
+// AddInterruptedResult adds a game stopped before it finished. Only its
+// prize money counts: each player's ICM equity, or prize if they had
+// already busted. Places, chips and the other statistics need a finished
+// game.
+func (tr *TournamentResult) AddInterruptedResult(result *GameResult) {
+	tr.InterruptedGames = append(tr.InterruptedGames, result)
+	for _, ranking := range result.PlayerRankings {
+		stats, exists := tr.PlayerStats[ranking.Player.Name]
+		if !exists {
+			stats = &PlayerStats{Name: ranking.Player.Name}
+			tr.PlayerStats[ranking.Player.Name] = stats
+		}
+		stats.InterruptedGames++
+		stats.Winnings += ranking.Payout
+		stats.AvgWinnings = stats.Winnings / float64(stats.TotalGames+stats.InterruptedGames)
 	}
 }
 
@@ -97,8 +235,21 @@ func (tr *TournamentResult) IsComplete() bool {
 	return tr.CompletedGames >= tr.TotalGames
 }
 
-// updateOverallWinner finds the player with the most wins
+// updateOverallWinner finds the player with the most wins, or in cash games
+// the player who won the most chips
 func (tr *TournamentResult) updateOverallWinner() {
+	if tr.CashGame {
+		tr.OverallWinner = ""
+		best := 0
+		for _, stats := range tr.PlayerStats {
+			if tr.OverallWinner == "" || stats.NetChips > best {
+				best = stats.NetChips
+				tr.OverallWinner = stats.Name
+			}
+		}
+		return
+	}
+
 	maxWins := 0
 	for _, stats := range tr.PlayerStats {
 		if stats.Wins > maxWins {